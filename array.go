@@ -20,7 +20,10 @@ type Array struct {
 // Example:
 //  array := NewArray(t, []interface{}{"foo", 123})
 func NewArray(reporter Reporter, value []interface{}) *Array {
-	chain := makeChain(reporter)
+	return makeArray(makeChain(reporter), value)
+}
+
+func makeArray(chain chain, value []interface{}) *Array {
 	if value == nil {
 		chain.fail("expected non-nil array value")
 	} else {
@@ -39,13 +42,37 @@ func (a *Array) Raw() []interface{} {
 	return a.value
 }
 
+// Require returns a copy of Array object that reports failures as fatal.
+// See Value.Require for details.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.Require().Length().Equal(2)
+func (a *Array) Require() *Array {
+	ret := *a
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Alias returns a copy of Array object with assertion path replaced
+// by given name. See Value.Alias for details.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.Alias("tags").Length().Equal(2)
+func (a *Array) Alias(name string) *Array {
+	ret := *a
+	ret.chain.alias(name)
+	return &ret
+}
+
 // Length returns a new Number object that may be used to inspect array length.
 //
 // Example:
 //  array := NewArray(t, []interface{}{1, 2, 3})
 //  array.Length().Equal(3)
 func (a *Array) Length() *Number {
-	return &Number{a.chain, float64(len(a.value))}
+	return &Number{a.chain.enter(".Length"), float64(len(a.value)), 0}
 }
 
 // Element returns a new Value object that may be used to inspect array element
@@ -62,9 +89,107 @@ func (a *Array) Element(index int) *Value {
 	if len(a.value) <= index {
 		a.chain.fail("\nexpected array of length > %d, but got array of length %d:\n%s",
 			index, len(a.value), dumpValue(a.value))
-		return &Value{a.chain, nil}
+		return &Value{a.chain.enter("[%d]", index), nil}
+	}
+	return &Value{a.chain.enter("[%d]", index), a.value[index]}
+}
+
+// First returns a new Value object that may be used to inspect first element
+// of the array.
+//
+// If array is empty, First reports failure and returns empty (but non-nil)
+// value.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.First().String().Equal("foo")
+func (a *Array) First() *Value {
+	if len(a.value) == 0 {
+		a.chain.fail("\nexpected non-empty array, but got empty array")
+		return &Value{a.chain.enter("[0]"), nil}
+	}
+	return &Value{a.chain.enter("[0]"), a.value[0]}
+}
+
+// Last returns a new Value object that may be used to inspect last element
+// of the array.
+//
+// If array is empty, Last reports failure and returns empty (but non-nil)
+// value.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.Last().Number().Equal(123)
+func (a *Array) Last() *Value {
+	if len(a.value) == 0 {
+		a.chain.fail("\nexpected non-empty array, but got empty array")
+		return &Value{a.chain.enter("[-1]"), nil}
+	}
+	index := len(a.value) - 1
+	return &Value{a.chain.enter("[%d]", index), a.value[index]}
+}
+
+// Slice returns a new Array object that may be used to inspect array
+// elements from begin (inclusive) to end (exclusive).
+//
+// If range is invalid, i.e. if begin is negative, end is less than begin,
+// or end is greater than array length, Slice reports failure and returns
+// empty (but non-nil) array.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123, "bar"})
+//  array.Slice(1, 3).Elements(123, "bar")
+func (a *Array) Slice(begin, end int) *Array {
+	if begin < 0 || end < begin || end > len(a.value) {
+		a.chain.fail("\nexpected valid slice range [%d:%d] for array of length %d:\n%s",
+			begin, end, len(a.value), dumpValue(a.value))
+		return &Array{a.chain.enter("[%d:%d]", begin, end), nil}
+	}
+	return &Array{a.chain.enter("[%d:%d]", begin, end), a.value[begin:end]}
+}
+
+// Map returns a new Array object with elements produced by calling given
+// function for every element of the array. Every element is wrapped into
+// a new Value object. Returned values are converted to canonical form.
+//
+// If array is failed, fn is not invoked and empty array is returned.
+//
+// Example:
+//  array := NewArray(t, []interface{}{
+//      map[string]interface{}{"id": 1},
+//      map[string]interface{}{"id": 2},
+//  })
+//  ids := array.Map(func(index int, value *Value) interface{} {
+//      return value.Object().Value("id").Raw()
+//  })
+//  ids.ContainsOnly(1, 2)
+func (a *Array) Map(fn func(index int, value *Value) interface{}) *Array {
+	if a.chain.failed() {
+		return &Array{a.chain.enter(".Map"), []interface{}{}}
 	}
-	return &Value{a.chain, a.value[index]}
+	mapped := make([]interface{}, 0, len(a.value))
+	for i, e := range a.value {
+		mapped = append(mapped, fn(i, &Value{a.chain.enter("[%d]", i), e}))
+	}
+	values, ok := canonArray(&a.chain, mapped)
+	if !ok {
+		return &Array{a.chain.enter(".Map"), []interface{}{}}
+	}
+	return &Array{a.chain.enter(".Map"), values}
+}
+
+// Decode unmarshals underlying value into target, using JSON round-trip
+// conversion. target should be a non-nil pointer, e.g. to a slice or an array.
+//
+// Example:
+//  var names []string
+//  resp.JSON().Array().Decode(&names)
+func (a *Array) Decode(target interface{}) *Array {
+	if a.chain.failed() {
+		return a
+	}
+	decodeValue(&a.chain, a.value, target)
+	return a
 }
 
 // Empty succeedes if array is empty.
@@ -90,6 +215,9 @@ func (a *Array) NotEmpty() *Array {
 //
 // value should be slice of any type.
 //
+// On failure, the message includes a diff between expected and actual
+// arrays, with changed elements marked by "-" and "+" and their indices.
+//
 // Example:
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.Equal([]interface{}{"foo", 123})
@@ -105,7 +233,8 @@ func (a *Array) Equal(value interface{}) *Array {
 		return a
 	}
 	if !reflect.DeepEqual(expected, a.value) {
-		a.chain.fail("\nexpected array equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+		a.chain.failValues("Equal", expected, a.value,
+			"\nexpected array equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
 			dumpValue(expected),
 			dumpValue(a.value),
 			diffValues(expected, a.value))
@@ -127,7 +256,8 @@ func (a *Array) NotEqual(value interface{}) *Array {
 		return a
 	}
 	if reflect.DeepEqual(expected, a.value) {
-		a.chain.fail("\nexpected array NOT equal to:\n%s",
+		a.chain.failValues("NotEqual", expected, a.value,
+			"\nexpected array NOT equal to:\n%s",
 			dumpValue(expected))
 	}
 	return a
@@ -193,6 +323,10 @@ func (a *Array) NotContains(values ...interface{}) *Array {
 // ContainsOnly succeedes if array contains all given elements, in any order, and only
 // them. Before comparison, array and all elements are converted to canonical form.
 //
+// ContainsOnly checks that array has the same length as the list of given elements
+// and that every given element is present in array, but it doesn't compare how many
+// times every element occurs. Use ContainsExactly to compare multiplicity too.
+//
 // Example:
 //  array := NewArray(t, []interface{}{"foo", 123})
 //  array.ContainsOnly(123, "foo")
@@ -221,6 +355,262 @@ func (a *Array) ContainsOnly(values ...interface{}) *Array {
 	return a
 }
 
+// ContainsExactly succeedes if array contains given elements, in any order,
+// and every element occurs in array the same number of times as in the list
+// of given elements. Before comparison, array and all elements are converted
+// to canonical form.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123, "foo"})
+//  array.ContainsExactly("foo", "foo", 123)  // success
+//  array.ContainsExactly("foo", 123, 123)    // failure
+func (a *Array) ContainsExactly(values ...interface{}) *Array {
+	a.checkUnordered("ContainsExactly", values)
+	return a
+}
+
+// EqualUnordered succeedes if array contains given elements in any order,
+// i.e. if array is equal to given list of elements when both are treated
+// as multisets. Before comparison, array and all elements are converted
+// to canonical form.
+//
+// It's equivalent to ContainsExactly.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123, "foo"})
+//  array.EqualUnordered(123, "foo", "foo")  // success
+//  array.EqualUnordered("foo", 123)         // failure
+func (a *Array) EqualUnordered(values ...interface{}) *Array {
+	a.checkUnordered("EqualUnordered", values)
+	return a
+}
+
+// checkUnordered implements ContainsExactly and EqualUnordered; failure
+// message lists which expected elements are missing and which actual
+// elements are extra
+func (a *Array) checkUnordered(assertion string, values []interface{}) {
+	elements, ok := canonArray(&a.chain, values)
+	if !ok {
+		return
+	}
+
+	missing := []interface{}{}
+	extra := []interface{}{}
+
+	for _, c := range countElements(elements, a.value) {
+		for i := c.actual; i < c.expected; i++ {
+			missing = append(missing, c.value)
+		}
+		for i := c.expected; i < c.actual; i++ {
+			extra = append(extra, c.value)
+		}
+	}
+
+	if len(missing) != 0 || len(extra) != 0 {
+		a.chain.failValues(assertion, elements, a.value,
+			"\nexpected array containing exactly (in any order) elements:\n%s\n\n"+
+				"but got:\n%s\n\nmissing elements:\n%s\n\nextra elements:\n%s",
+			dumpValue(elements), dumpValue(a.value),
+			dumpValue(missing), dumpValue(extra))
+	}
+}
+
+type elementCount struct {
+	value    interface{}
+	expected int
+	actual   int
+}
+
+// countElements returns number of occurrences of every distinct value
+// in expected and actual lists, in order of first occurrence
+func countElements(expected, actual []interface{}) []*elementCount {
+	var counts []*elementCount
+
+	lookup := func(value interface{}) *elementCount {
+		for _, c := range counts {
+			if reflect.DeepEqual(c.value, value) {
+				return c
+			}
+		}
+		c := &elementCount{value: value}
+		counts = append(counts, c)
+		return c
+	}
+
+	for _, e := range expected {
+		lookup(e).expected++
+	}
+	for _, e := range actual {
+		lookup(e).actual++
+	}
+
+	return counts
+}
+
+// Unique succeedes if array contains no duplicate elements, i.e. if no two
+// elements are equal in canonical form.
+//
+// Example:
+//  array := NewArray(t, []interface{}{"foo", 123})
+//  array.Unique()
+func (a *Array) Unique() *Array {
+	if a.chain.failed() {
+		return a
+	}
+	for i := range a.value {
+		for j := i + 1; j < len(a.value); j++ {
+			if reflect.DeepEqual(a.value[i], a.value[j]) {
+				a.chain.fail("\nexpected array with unique elements, "+
+					"but elements %d and %d are equal:\n%s\n\nin array:\n%s",
+					i, j, dumpValue(a.value[i]), dumpValue(a.value))
+				return a
+			}
+		}
+	}
+	return a
+}
+
+// EveryType succeedes if every array element has given JSON type, which
+// is one of "object", "array", "string", "number", "boolean", or "null".
+// Empty array always succeedes.
+//
+// If some elements have another type, failure is reported for the first
+// of them.
+//
+// Example:
+//  array := NewArray(t, []interface{}{1, 2, 3})
+//  array.EveryType("number")
+func (a *Array) EveryType(typ string) *Array {
+	if a.chain.failed() {
+		return a
+	}
+	switch typ {
+	case "object", "array", "string", "number", "boolean", "null":
+	default:
+		a.chain.fail("\nunexpected type %q in EveryType, expected one of: "+
+			"object, array, string, number, boolean, null", typ)
+		return a
+	}
+	for i, e := range a.value {
+		if actual := jsonType(e); actual != typ {
+			a.chain.fail("\nexpected array with elements of type:\n  %s\n\n"+
+				"but got element %d of type %s:\n%s\n\nin array:\n%s",
+				typ, i, actual, dumpValue(e), dumpValue(a.value))
+			return a
+		}
+	}
+	return a
+}
+
+// SortOrder defines order of array elements for IsSorted.
+type SortOrder int
+
+const (
+	// Ascending order: every element is less than or equal to the next one.
+	Ascending SortOrder = iota
+
+	// Descending order: every element is greater than or equal to the next one.
+	Descending
+)
+
+// String returns name of sort order.
+func (o SortOrder) String() string {
+	switch o {
+	case Ascending:
+		return "ascending"
+	case Descending:
+		return "descending"
+	}
+	return "unknown"
+}
+
+// IsSorted succeedes if array elements are sorted in given order.
+//
+// Array should contain either only numbers or only strings. Numbers are
+// compared numerically, and strings are compared lexically (byte-wise).
+// Equal adjacent elements are allowed. Empty array is always sorted.
+//
+// Example:
+//  array := NewArray(t, []interface{}{1, 2, 2, 3})
+//  array.IsSorted(Ascending)
+//
+//  array := NewArray(t, []interface{}{"c", "b", "a"})
+//  array.IsSorted(Descending)
+func (a *Array) IsSorted(order SortOrder) *Array {
+	if a.chain.failed() {
+		return a
+	}
+
+	if order != Ascending && order != Descending {
+		a.chain.fail("\nunexpected sort order %d in IsSorted", int(order))
+		return a
+	}
+
+	if len(a.value) == 0 {
+		return a
+	}
+
+	var less func(x, y interface{}) bool
+
+	switch a.value[0].(type) {
+	case float64:
+		less = func(x, y interface{}) bool {
+			return x.(float64) < y.(float64)
+		}
+	case string:
+		less = func(x, y interface{}) bool {
+			return x.(string) < y.(string)
+		}
+	}
+
+	for i, e := range a.value {
+		if less == nil || reflect.TypeOf(e) != reflect.TypeOf(a.value[0]) {
+			a.chain.fail("\nexpected array of numbers or array of strings, "+
+				"but got element %d of type %s:\n%s",
+				i, jsonType(e), dumpValue(a.value))
+			return a
+		}
+	}
+
+	if order == Descending {
+		asc := less
+		less = func(x, y interface{}) bool {
+			return asc(y, x)
+		}
+	}
+
+	return a.checkSorted(order.String()+" order", less)
+}
+
+// IsSortedBy succeedes if array elements are sorted according to given less
+// function, i.e. if less(a[i+1], a[i]) is false for every i.
+//
+// less receives array elements in canonical form (see Raw).
+//
+// Example:
+//  array := NewArray(t, []interface{}{"a", "bb", "ccc"})
+//  array.IsSortedBy(func(x, y interface{}) bool {
+//      return len(x.(string)) < len(y.(string))
+//  })
+func (a *Array) IsSortedBy(less func(x, y interface{}) bool) *Array {
+	if a.chain.failed() {
+		return a
+	}
+	return a.checkSorted("order given by less function", less)
+}
+
+func (a *Array) checkSorted(order string, less func(x, y interface{}) bool) *Array {
+	for i := 1; i < len(a.value); i++ {
+		if less(a.value[i], a.value[i-1]) {
+			a.chain.fail("\nexpected array sorted in %s, "+
+				"but element %d is out of order:\n%s",
+				order, i, dumpValue(a.value))
+			return a
+		}
+	}
+	return a
+}
+
 func (a *Array) containsElement(expected interface{}) bool {
 	for _, e := range a.value {
 		if reflect.DeepEqual(expected, e) {
@@ -0,0 +1,104 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// ArrayStream provides methods to inspect JSON array elements one by one,
+// decoding them on the fly from response body instead of decoding the whole
+// array at once.
+//
+// ArrayStream is returned by Response.JSONStream. If it decodes elements
+// directly from the connection (see Request.WithStreaming), it may be
+// iterated only once; otherwise, every ForEach or Count call iterates
+// buffered body again.
+type ArrayStream struct {
+	chain    chain
+	content  []byte
+	body     io.ReadCloser
+	consumed bool
+}
+
+// ForEach decodes array elements one by one and calls given function for
+// every element, with element index and a new Value object that may be
+// used to inspect it.
+//
+// Decoded element is not retained after the function returns.
+//
+// Example:
+//  stream := resp.JSONStream()
+//  stream.ForEach(func(index int, value *httpexpect.Value) {
+//      value.Object().ContainsKey("id")
+//  })
+func (s *ArrayStream) ForEach(fn func(index int, value *Value)) *ArrayStream {
+	s.iterate(func(index int, value interface{}) {
+		fn(index, &Value{s.chain.enter("[%d]", index), value})
+	})
+	return s
+}
+
+// Count returns a new Number object that may be used to inspect number
+// of array elements.
+//
+// Example:
+//  stream := resp.JSONStream()
+//  stream.Count().Equal(1000)
+func (s *ArrayStream) Count() *Number {
+	count := 0
+	s.iterate(func(int, interface{}) {
+		count++
+	})
+	return &Number{s.chain.enter(".Count"), float64(count), 0}
+}
+
+func (s *ArrayStream) iterate(fn func(index int, value interface{})) {
+	if s.chain.failed() {
+		return
+	}
+
+	var reader io.Reader = bytes.NewReader(s.content)
+
+	if s.body != nil {
+		if s.consumed {
+			s.chain.fail("\nexpected JSON array stream that is not consumed yet," +
+				" but it was already iterated")
+			return
+		}
+		s.consumed = true
+		defer s.body.Close()
+		reader = s.body
+	}
+
+	dec := json.NewDecoder(reader)
+
+	tok, err := dec.Token()
+	if err != nil {
+		s.chain.fail(err.Error())
+		return
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		s.chain.fail("\nexpected JSON array, but got:\n  %v", tok)
+		return
+	}
+
+	for index := 0; dec.More(); index++ {
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			s.chain.fail(err.Error())
+			return
+		}
+		fn(index, value)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		s.chain.fail(err.Error())
+		return
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		s.chain.fail("\nexpected single JSON array, but got trailing data")
+	}
+}
@@ -0,0 +1,218 @@
+package httpexpect
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestArrayStreamFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	stream := &ArrayStream{chain: chain, content: []byte(`[1, 2]`)}
+
+	called := false
+	stream.ForEach(func(int, *Value) {
+		called = true
+	})
+	assert.False(t, called)
+
+	stream.Count().chain.assertFailed(t)
+}
+
+func TestArrayStream(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`[1, "a", {"b": 2}]`)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	stream := resp.JSONStream()
+
+	stream.Count().Equal(3)
+	stream.chain.assertOK(t)
+
+	var indexes []int
+	var values []interface{}
+
+	stream.ForEach(func(index int, value *Value) {
+		indexes = append(indexes, index)
+		values = append(values, value.Raw())
+		value.chain.assertOK(t)
+	})
+	stream.chain.assertOK(t)
+
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+	assert.Equal(t, []interface{}{
+		1.0, "a", map[string]interface{}{"b": 2.0}}, values)
+}
+
+func TestArrayStreamEmpty(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	stream := &ArrayStream{chain: makeChain(reporter), content: []byte(`[]`)}
+
+	stream.ForEach(func(int, *Value) {
+		t.Fail()
+	})
+	stream.Count().Equal(0)
+	stream.chain.assertOK(t)
+}
+
+func TestArrayStreamBadContent(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	bad := []string{
+		``,
+		`{"a": 1}`,
+		`"a"`,
+		`[1, 2`,
+		`[1, }`,
+		`[1] [2]`,
+	}
+
+	for _, content := range bad {
+		stream := &ArrayStream{chain: makeChain(reporter), content: []byte(content)}
+		stream.Count()
+		stream.chain.assertFailed(t)
+	}
+}
+
+func TestArrayStreamContentType(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"text/plain"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`[1]`)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.JSONStream().chain.assertFailed(t)
+}
+
+func TestArrayStreamStreaming(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`[1, 2, 3]`)),
+	}
+
+	resp := makeResponse(makeChain(reporter), httpResp, 0, true, 0, true)
+	resp.chain.assertOK(t)
+
+	stream := resp.JSONStream()
+	stream.Count().Equal(3)
+	stream.chain.assertOK(t)
+
+	assert.Nil(t, resp.content.data)
+
+	stream.Count().chain.assertFailed(t)
+
+	resp.JSONStream().chain.assertFailed(t)
+	resp.Body().chain.assertFailed(t)
+}
+
+func TestArrayStreamStreamingBuffered(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`[1, 2, 3]`)),
+	}
+
+	resp := makeResponse(makeChain(reporter), httpResp, 0, true, 0, true)
+
+	resp.Body().Equal(`[1, 2, 3]`)
+
+	stream := resp.JSONStream()
+	stream.Count().Equal(3)
+	stream.Count().Equal(3)
+	stream.chain.assertOK(t)
+}
+
+func TestArrayStreamStreamingDecompress(t *testing.T) {
+	body := `[{"a": 1}, {"b": 2}]`
+
+	var gzipBody bytes.Buffer
+	gw := gzip.NewWriter(&gzipBody)
+	gw.Write([]byte(body))
+	gw.Close()
+
+	var flateBody bytes.Buffer
+	fw, _ := flate.NewWriter(&flateBody, flate.DefaultCompression)
+	fw.Write([]byte(body))
+	fw.Close()
+
+	cases := []struct {
+		encoding string
+		content  []byte
+	}{
+		{"", []byte(body)},
+		{"gzip", gzipBody.Bytes()},
+		{"deflate", flateBody.Bytes()},
+	}
+
+	for _, tc := range cases {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type":     {"application/json"},
+				"Content-Encoding": {tc.encoding},
+			},
+			Body: ioutil.NopCloser(bytes.NewReader(tc.content)),
+		}
+
+		resp := makeResponse(makeChain(reporter), httpResp, 0, true, 0, true)
+
+		var values []interface{}
+		resp.JSONStream().ForEach(func(index int, value *Value) {
+			values = append(values, value.Raw())
+		}).chain.assertOK(t)
+
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"a": 1.0},
+			map[string]interface{}{"b": 2.0},
+		}, values)
+	}
+
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type":     {"application/json"},
+			"Content-Encoding": {"gzip"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`[1]`)),
+	}
+
+	resp := makeResponse(makeChain(reporter), httpResp, 0, true, 0, true)
+
+	resp.JSONStream().chain.assertFailed(t)
+	resp.chain.assertFailed(t)
+}
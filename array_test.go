@@ -16,9 +16,15 @@ func TestArrayFailed(t *testing.T) {
 
 	assert.False(t, value.Length() == nil)
 	assert.False(t, value.Element(0) == nil)
+	assert.False(t, value.First() == nil)
+	assert.False(t, value.Last() == nil)
+	assert.False(t, value.Slice(0, 0) == nil)
 
 	value.Length().chain.assertFailed(t)
 	value.Element(0).chain.assertFailed(t)
+	value.First().chain.assertFailed(t)
+	value.Last().chain.assertFailed(t)
+	value.Slice(0, 0).chain.assertFailed(t)
 
 	value.Empty()
 	value.NotEmpty()
@@ -28,6 +34,22 @@ func TestArrayFailed(t *testing.T) {
 	value.Contains("foo")
 	value.NotContains("foo")
 	value.ContainsOnly("foo")
+	value.ContainsExactly("foo")
+	value.EqualUnordered("foo")
+	value.EveryType("string")
+
+	value.Map(func(int, *Value) interface{} {
+		t.Fail()
+		return nil
+	}).chain.assertFailed(t)
+	value.Unique()
+	value.IsSorted(Ascending)
+	value.IsSortedBy(func(x, y interface{}) bool {
+		return false
+	})
+
+	var target interface{}
+	value.Decode(&target)
 }
 
 func TestArrayGetters(t *testing.T) {
@@ -47,6 +69,52 @@ func TestArrayGetters(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestArrayFirstLast(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewArray(reporter, []interface{}{"foo", 123.0})
+
+	assert.Equal(t, "foo", value1.First().Raw())
+	assert.Equal(t, 123.0, value1.Last().Raw())
+	value1.chain.assertOK(t)
+
+	value2 := NewArray(reporter, []interface{}{})
+
+	assert.Equal(t, nil, value2.First().Raw())
+	value2.chain.assertFailed(t)
+	value2.chain.reset()
+
+	assert.Equal(t, nil, value2.Last().Raw())
+	value2.chain.assertFailed(t)
+	value2.chain.reset()
+}
+
+func TestArraySlice(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", 123.0, "bar"})
+
+	assert.Equal(t, []interface{}{123.0, "bar"}, value.Slice(1, 3).Raw())
+	assert.Equal(t, []interface{}{"foo"}, value.Slice(0, 1).Raw())
+	assert.Equal(t, []interface{}{}, value.Slice(3, 3).Raw())
+	value.chain.assertOK(t)
+
+	value.Slice(1, 3).Elements(123, "bar")
+	value.chain.assertOK(t)
+
+	value.Slice(-1, 1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Slice(2, 1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Slice(1, 4)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
 func TestArrayEmpty(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -310,6 +378,258 @@ func TestArrayContainsOnly(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestArrayContainsExactly(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", 123, "foo"})
+
+	value.ContainsExactly("foo", "foo", 123)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsExactly(123, "foo", "foo")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsExactly("foo", 123)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ContainsExactly("foo", 123, 123)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ContainsExactly("foo", "foo", 123, "bar")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	// ContainsOnly doesn't compare multiplicity
+	value.ContainsOnly("foo", 123, 123)
+	value.chain.assertOK(t)
+	value.chain.reset()
+	collector := NewFailureCollector(nil)
+
+	NewArray(collector, []interface{}{"foo", 123, "foo"}).
+		ContainsExactly("foo", 123, 123, "bar")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 1, len(failures))
+	assert.Equal(t, "ContainsExactly", failures[0].Assertion)
+	assert.Contains(t, failures[0].Message,
+		"missing elements:\n"+dumpValue([]interface{}{123, "bar"}))
+	assert.Contains(t, failures[0].Message,
+		"extra elements:\n"+dumpValue([]interface{}{"foo"}))
+}
+
+func TestArrayEqualUnordered(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", 123, "foo"})
+
+	value.EqualUnordered("foo", "foo", 123)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualUnordered(123, "foo", "foo")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualUnordered("foo", 123)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualUnordered("foo", "foo", 123, "bar")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	collector := NewFailureCollector(nil)
+
+	NewArray(collector, []interface{}{"foo", 123, "foo"}).
+		EqualUnordered("foo", 123, 123, "bar")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 1, len(failures))
+	assert.Equal(t, "EqualUnordered", failures[0].Assertion)
+	assert.Contains(t, failures[0].Message,
+		"missing elements:\n"+dumpValue([]interface{}{123, "bar"}))
+	assert.Contains(t, failures[0].Message,
+		"extra elements:\n"+dumpValue([]interface{}{"foo"}))
+}
+
+func TestArrayUnique(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewArray(reporter, []interface{}{})
+
+	value1.Unique()
+	value1.chain.assertOK(t)
+
+	value2 := NewArray(reporter, []interface{}{"foo", 123, "bar", 1.5,
+		map[string]interface{}{"a": 1}})
+
+	value2.Unique()
+	value2.chain.assertOK(t)
+
+	value3 := NewArray(reporter, []interface{}{"foo", 123, "bar", 123.0})
+
+	value3.Unique()
+	value3.chain.assertFailed(t)
+
+	value4 := NewArray(reporter, []interface{}{
+		map[string]interface{}{"a": 1},
+		map[string]interface{}{"a": 1.0},
+	})
+
+	value4.Unique()
+	value4.chain.assertFailed(t)
+}
+
+func TestArrayEveryType(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := []struct {
+		value []interface{}
+		typ   string
+	}{
+		{[]interface{}{map[string]interface{}{}}, "object"},
+		{[]interface{}{[]interface{}{}, []interface{}{1}}, "array"},
+		{[]interface{}{"a", "b"}, "string"},
+		{[]interface{}{1, 2.5}, "number"},
+		{[]interface{}{true, false}, "boolean"},
+		{[]interface{}{nil, nil}, "null"},
+	}
+
+	for _, tc := range cases {
+		value := NewArray(reporter, tc.value)
+
+		value.EveryType(tc.typ)
+		value.chain.assertOK(t)
+		value.chain.reset()
+
+		other := "string"
+		if tc.typ == "string" {
+			other = "number"
+		}
+
+		value.EveryType(other)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	}
+
+	value := NewArray(reporter, []interface{}{1, "2", 3})
+
+	value.EveryType("number")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EveryType("integer")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewArray(reporter, []interface{}{}).EveryType("number").chain.assertOK(t)
+}
+
+func TestArrayMap(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{
+		map[string]interface{}{"id": 1, "name": "foo"},
+		map[string]interface{}{"id": 2, "name": "bar"},
+	})
+
+	var indexes []int
+
+	ids := value.Map(func(index int, value *Value) interface{} {
+		indexes = append(indexes, index)
+		return value.Object().Value("id").Raw()
+	})
+
+	ids.chain.assertOK(t)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, []int{0, 1}, indexes)
+	assert.Equal(t, []interface{}{1.0, 2.0}, ids.Raw())
+
+	ids.ContainsOnly(2, 1)
+	ids.chain.assertOK(t)
+
+	bad := value.Map(func(int, *Value) interface{} {
+		return make(chan int)
+	})
+
+	bad.chain.assertFailed(t)
+	value.chain.assertFailed(t)
+	assert.Equal(t, []interface{}{}, bad.Raw())
+}
+
+func TestArrayIsSorted(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := []struct {
+		value      []interface{}
+		ascending  bool
+		descending bool
+	}{
+		{[]interface{}{}, true, true},
+		{[]interface{}{1}, true, true},
+		{[]interface{}{1, 2, 2, 10}, true, false},
+		{[]interface{}{10, 2, 2, 1}, false, true},
+		{[]interface{}{1, 3, 2}, false, false},
+		{[]interface{}{"a", "b", "bb"}, true, false},
+		{[]interface{}{"b", "a", "A"}, false, true},
+		{[]interface{}{"10", "9"}, true, false},
+		{[]interface{}{1, "a"}, false, false},
+		{[]interface{}{"a", 1}, false, false},
+		{[]interface{}{true, false}, false, false},
+		{[]interface{}{nil, nil}, false, false},
+	}
+
+	for _, tc := range cases {
+		value := NewArray(reporter, tc.value)
+
+		value.IsSorted(Ascending)
+		if tc.ascending {
+			value.chain.assertOK(t)
+		} else {
+			value.chain.assertFailed(t)
+		}
+		value.chain.reset()
+
+		value.IsSorted(Descending)
+		if tc.descending {
+			value.chain.assertOK(t)
+		} else {
+			value.chain.assertFailed(t)
+		}
+		value.chain.reset()
+	}
+
+	value := NewArray(reporter, []interface{}{})
+
+	value.IsSorted(SortOrder(100))
+	value.chain.assertFailed(t)
+}
+
+func TestArrayIsSortedBy(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	less := func(x, y interface{}) bool {
+		return len(x.(string)) < len(y.(string))
+	}
+
+	value1 := NewArray(reporter, []interface{}{"c", "aa", "bb", "aaa"})
+
+	value1.IsSortedBy(less)
+	value1.chain.assertOK(t)
+
+	value2 := NewArray(reporter, []interface{}{"aa", "c"})
+
+	value2.IsSortedBy(less)
+	value2.chain.assertFailed(t)
+}
+
 func TestArrayConvertEqual(t *testing.T) {
 	type (
 		myArray []interface{}
@@ -414,3 +734,25 @@ func TestArrayConvertContains(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestArrayDecode(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewArray(reporter, []interface{}{"foo", "bar"})
+
+	var slice []string
+	value.Decode(&slice)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, []string{"foo", "bar"}, slice)
+
+	var array [2]string
+	value.Decode(&array)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, [2]string{"foo", "bar"}, array)
+
+	var numbers []int
+	value.Decode(&numbers)
+	value.chain.assertFailed(t)
+}
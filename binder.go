@@ -1,6 +1,8 @@
 package httpexpect
 
 import (
+	"context"
+	"crypto/tls"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -12,16 +14,71 @@ import (
 // It passes httptest.ResponseRecorder as http.ResponseWriter to the handler,
 // and then construct http.Response from recorded data.
 type Binder struct {
-	handler http.Handler
+	handler    http.Handler
+	context    context.Context
+	remoteAddr string
+	tls        *tls.ConnectionState
 }
 
-// NewBinder returns a new Binder given http.Handler.
-func NewBinder(handler http.Handler) *Binder {
-	return &Binder{handler}
+// BinderOption defines an option for NewBinder.
+type BinderOption func(*Binder)
+
+// WithBaseContext returns BinderOption that sets context for requests
+// passed to handler, if request has no context of its own.
+func WithBaseContext(ctx context.Context) BinderOption {
+	return func(binder *Binder) {
+		binder.context = ctx
+	}
+}
+
+// WithRemoteAddr returns BinderOption that sets RemoteAddr field of
+// requests passed to handler, e.g. "1.2.3.4:5678".
+func WithRemoteAddr(addr string) BinderOption {
+	return func(binder *Binder) {
+		binder.remoteAddr = addr
+	}
+}
+
+// WithTLS returns BinderOption that sets TLS field of requests passed
+// to handler, so that handler sees them as received via TLS connection.
+func WithTLS(state *tls.ConnectionState) BinderOption {
+	return func(binder *Binder) {
+		binder.tls = state
+	}
+}
+
+// NewBinder returns a new Binder given http.Handler and options.
+//
+// Example:
+//  binder := httpexpect.NewBinder(handler,
+//      httpexpect.WithTLS(&tls.ConnectionState{}),
+//      httpexpect.WithRemoteAddr("1.2.3.4:5678"))
+func NewBinder(handler http.Handler, options ...BinderOption) *Binder {
+	binder := &Binder{handler: handler}
+	for _, option := range options {
+		option(binder)
+	}
+	return binder
 }
 
 // Do implements Client.Do.
 func (binder *Binder) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if binder.context != nil && ctx == context.Background() {
+		ctx = binder.context
+	}
+
+	// shallow copy, to avoid modifying caller's request
+	req = req.WithContext(ctx)
+
+	if binder.remoteAddr != "" {
+		req.RemoteAddr = binder.remoteAddr
+	}
+
+	if binder.tls != nil {
+		req.TLS = binder.tls
+	}
+
 	recorder := httptest.NewRecorder()
 
 	binder.handler.ServeHTTP(recorder, req)
@@ -39,3 +96,33 @@ func (binder *Binder) Do(req *http.Request) (*http.Response, error) {
 
 	return &resp, nil
 }
+
+// RoundTripperClient implements Client using given http.RoundTripper.
+//
+// RoundTripperClient passes requests directly to RoundTrip method, so that
+// any http.RoundTripper (e.g. a mock transport) may be used as Client.
+// Unlike http.Client, it doesn't follow redirects and doesn't handle
+// cookies.
+type RoundTripperClient struct {
+	transport http.RoundTripper
+}
+
+// NewRoundTripperClient returns a new RoundTripperClient given
+// http.RoundTripper. If transport is nil, http.DefaultTransport is used.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      Client:   httpexpect.NewRoundTripperClient(transport),
+//      Reporter: httpexpect.NewAssertReporter(t),
+//  })
+func NewRoundTripperClient(transport http.RoundTripper) *RoundTripperClient {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &RoundTripperClient{transport}
+}
+
+// Do implements Client.Do.
+func (c *RoundTripperClient) Do(req *http.Request) (*http.Response, error) {
+	return c.transport.RoundTrip(req)
+}
@@ -2,6 +2,9 @@ package httpexpect
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
@@ -54,3 +57,111 @@ func TestBinder(t *testing.T) {
 	assert.Equal(t, header, resp.Header)
 	assert.Equal(t, `{"hello":"world"}`, string(b))
 }
+
+type binderContextKey struct{}
+
+func TestBinderOptions(t *testing.T) {
+	var (
+		remoteAddr string
+		tlsState   *tls.ConnectionState
+		ctxValue   interface{}
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+		tlsState = r.TLS
+		ctxValue = r.Context().Value(binderContextKey{})
+	})
+
+	state := &tls.ConnectionState{ServerName: "example.com"}
+
+	ctx := context.WithValue(context.Background(), binderContextKey{}, "value")
+
+	binder := NewBinder(handler,
+		WithBaseContext(ctx),
+		WithRemoteAddr("1.2.3.4:5678"),
+		WithTLS(state))
+
+	req, err := http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = binder.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "1.2.3.4:5678", remoteAddr)
+	assert.True(t, tlsState == state)
+	assert.Equal(t, "value", ctxValue)
+
+	assert.Equal(t, "", req.RemoteAddr)
+	assert.Nil(t, req.TLS)
+
+	ownCtx := context.WithValue(context.Background(), binderContextKey{}, "own")
+
+	_, err = binder.Do(req.WithContext(ownCtx))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "own", ctxValue)
+}
+
+type mockTransport struct {
+	req  *http.Request
+	resp *http.Response
+	err  error
+}
+
+func (rt *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.req = req
+	return rt.resp, rt.err
+}
+
+func TestRoundTripperClient(t *testing.T) {
+	transport := &mockTransport{
+		resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"application/json"},
+			},
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{"hello":"world"}`)),
+		},
+	}
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewRoundTripperClient(transport),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/path").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().ValueEqual("hello", "world")
+
+	assert.Equal(t, "http://example.com/path", transport.req.URL.String())
+}
+
+func TestRoundTripperClientError(t *testing.T) {
+	transport := &mockTransport{
+		err: errors.New("error"),
+	}
+
+	client := NewRoundTripperClient(transport)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	resp, err := client.Do(req)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, transport.err, err)
+}
+
+func TestRoundTripperClientDefault(t *testing.T) {
+	client := NewRoundTripperClient(nil)
+
+	assert.Equal(t, http.DefaultTransport, client.transport)
+}
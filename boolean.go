@@ -28,6 +28,30 @@ func (b *Boolean) Raw() bool {
 	return b.value
 }
 
+// Require returns a copy of Boolean object that reports failures as fatal.
+// See Value.Require for details.
+//
+// Example:
+//  boolean := NewBoolean(t, true)
+//  boolean.Require().True()
+func (b *Boolean) Require() *Boolean {
+	ret := *b
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Alias returns a copy of Boolean object with assertion path replaced
+// by given name. See Value.Alias for details.
+//
+// Example:
+//  boolean := NewBoolean(t, true)
+//  boolean.Alias("enabled").True()
+func (b *Boolean) Alias(name string) *Boolean {
+	ret := *b
+	ret.chain.alias(name)
+	return &ret
+}
+
 // Equal succeedes if boolean is equal to given value.
 //
 // Example:
@@ -35,7 +59,8 @@ func (b *Boolean) Raw() bool {
 //  boolean.Equal(true)
 func (b *Boolean) Equal(value bool) *Boolean {
 	if !(b.value == value) {
-		b.chain.fail("expected boolean == %v, but got %v", value, b.value)
+		b.chain.failValues("Equal", value, b.value,
+			"expected boolean == %v, but got %v", value, b.value)
 	}
 	return b
 }
@@ -47,11 +72,34 @@ func (b *Boolean) Equal(value bool) *Boolean {
 //  boolean.NotEqual(false)
 func (b *Boolean) NotEqual(value bool) *Boolean {
 	if !(b.value != value) {
-		b.chain.fail("expected boolean != %v, but got %v", value, b.value)
+		b.chain.failValues("NotEqual", value, b.value,
+			"expected boolean != %v, but got %v", value, b.value)
 	}
 	return b
 }
 
+// EqualValue succeedes if given value is a boolean equal to boolean.
+// Before comparison, value is converted to canonical form.
+//
+// It's useful for values extracted from generic JSON, which have
+// interface{} type. If value is not a boolean, failure is reported.
+//
+// Example:
+//  boolean := NewBoolean(t, true)
+//  boolean.EqualValue(object.Raw()["enabled"])
+func (b *Boolean) EqualValue(value interface{}) *Boolean {
+	data, ok := canonValue(&b.chain, value)
+	if !ok {
+		return b
+	}
+	expected, ok := data.(bool)
+	if !ok {
+		b.chain.fail("\nexpected boolean value, but got:\n%s", dumpValue(value))
+		return b
+	}
+	return b.Equal(expected)
+}
+
 // True succeedes if boolean is true.
 //
 // Example:
@@ -16,6 +16,7 @@ func TestBooleanFailed(t *testing.T) {
 
 	value.Equal(false)
 	value.NotEqual(false)
+	value.EqualValue(false)
 	value.True()
 	value.False()
 }
@@ -83,3 +84,37 @@ func TestBooleanFalse(t *testing.T) {
 	value.chain.assertOK(t)
 	value.chain.reset()
 }
+
+func TestBooleanEqualValue(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewBoolean(reporter, true)
+
+	value.EqualValue(true)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualValue(interface{}(true))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualValue(false)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualValue("true")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualValue(1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualValue(nil)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualValue(make(chan int))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
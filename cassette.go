@@ -0,0 +1,219 @@
+package httpexpect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// RecordMode defines how client returned by NewCassetteClient uses its
+// cassette file.
+type RecordMode int
+
+const (
+	// Auto replays recorded responses, and records responses for
+	// requests not found in the cassette.
+	Auto RecordMode = iota
+
+	// Record sends every request and records its response, replacing
+	// previously recorded one.
+	Record
+
+	// Replay only replays recorded responses. Requests not found in
+	// the cassette cause an error.
+	Replay
+)
+
+type cassetteClient struct {
+	client  Client
+	path    string
+	mode    RecordMode
+	mutex   sync.Mutex
+	entries map[string]cassetteEntry
+	err     error
+}
+
+type cassetteEntry struct {
+	Key    string      `json:"key"`
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// NewCassetteClient returns a new Client that records request/response
+// pairs to a file (cassette) and replays them later, so that tests may be
+// run without network access. It's given inner client used to send
+// requests that should be recorded, cassette file path, and mode.
+//
+// Requests are matched by method, URL, and SHA-256 hash of the body.
+// The cassette is a JSON file, written after every recorded response.
+//
+// client may be nil if mode is Replay. If cassette file doesn't exist,
+// it's created when the first response is recorded.
+//
+// Errors (e.g. missing recorded response in Replay mode, or broken
+// cassette file) are returned from Do, and so are reported as failures
+// by Request.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.org",
+//      Client:   httpexpect.NewCassetteClient(
+//          http.DefaultClient, "testdata/cassette.json", httpexpect.Auto),
+//      Reporter: httpexpect.NewAssertReporter(t),
+//  })
+func NewCassetteClient(client Client, path string, mode RecordMode) Client {
+	c := &cassetteClient{
+		client:  client,
+		path:    path,
+		mode:    mode,
+		entries: make(map[string]cassetteEntry),
+	}
+	c.err = c.load()
+	return c
+}
+
+// Do implements Client.Do.
+func (c *cassetteClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		body = b
+	}
+
+	key := cassetteKey(req, body)
+
+	if c.mode != Record {
+		if entry, ok := c.entries[key]; ok {
+			return entry.response(req), nil
+		}
+	}
+
+	if c.mode == Replay {
+		return nil, fmt.Errorf("cassette %s: no recorded response for %s %s",
+			c.path, req.Method, req.URL)
+	}
+
+	if c.client == nil {
+		return nil, fmt.Errorf("cassette %s: no client to record response for %s %s",
+			c.path, req.Method, req.URL)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := cassetteEntry{
+		Key:    key,
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header,
+	}
+
+	if resp.Body != nil {
+		entry.Body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.entries[key] = entry
+
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+
+	return entry.response(req), nil
+}
+
+func (c *cassetteClient) load() error {
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var entries []cassetteEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("cassette %s: %s", c.path, err.Error())
+	}
+
+	for _, e := range entries {
+		c.entries[e.Key] = e
+	}
+
+	return nil
+}
+
+func (c *cassetteClient) save() error {
+	entries := make([]cassetteEntry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+
+	sort.Sort(cassetteEntries(entries))
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, data, 0644)
+}
+
+type cassetteEntries []cassetteEntry
+
+func (e cassetteEntries) Len() int           { return len(e) }
+func (e cassetteEntries) Less(i, j int) bool { return e[i].Key < e[j].Key }
+func (e cassetteEntries) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+
+func cassetteKey(req *http.Request, body []byte) string {
+	hash := sha256.Sum256(body)
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(hash[:])
+}
+
+func (e cassetteEntry) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range e.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	return &http.Response{
+		Request:       req,
+		Status:        strconv.Itoa(e.Status) + " " + http.StatusText(e.Status),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+	}
+}
@@ -0,0 +1,138 @@
+package httpexpect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createCassetteHandler(counter *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*counter++
+		var body []byte
+		if r.Body != nil {
+			body, _ = ioutil.ReadAll(r.Body)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	})
+}
+
+func createCassetteDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	dir := createCassetteDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassette.json")
+
+	counter := 0
+
+	binder := NewBinder(createCassetteHandler(&counter))
+
+	e1 := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewCassetteClient(binder, path, Auto),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e1.POST("/foo").WithText("a").Expect().
+		Status(http.StatusCreated).Text().Equal("POST /foo a")
+
+	e1.POST("/foo").WithText("b").Expect().
+		Status(http.StatusCreated).Text().Equal("POST /foo b")
+
+	e1.POST("/foo").WithText("a").Expect().
+		Status(http.StatusCreated).Text().Equal("POST /foo a")
+
+	assert.Equal(t, 2, counter)
+
+	e2 := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewCassetteClient(nil, path, Replay),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e2.POST("/foo").WithText("b").Expect().
+		Status(http.StatusCreated).Text().Equal("POST /foo b")
+
+	e2.POST("/foo").WithText("a").Expect().
+		Status(http.StatusCreated).Text().Equal("POST /foo a")
+
+	assert.Equal(t, 2, counter)
+
+	e3 := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewCassetteClient(binder, path, Record),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e3.POST("/foo").WithText("a").Expect().
+		Status(http.StatusCreated).Text().Equal("POST /foo a")
+
+	assert.Equal(t, 3, counter)
+}
+
+func TestCassetteMismatch(t *testing.T) {
+	dir := createCassetteDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassette.json")
+
+	counter := 0
+
+	binder := NewBinder(createCassetteHandler(&counter))
+
+	e1 := WithConfig(Config{
+		Client:   NewCassetteClient(binder, path, Auto),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e1.GET("http://example.com/foo").Expect().Status(http.StatusCreated)
+
+	reporter := newMockReporter(t)
+
+	e2 := WithConfig(Config{
+		Client:   NewCassetteClient(nil, path, Replay),
+		Reporter: reporter,
+	})
+
+	e2.GET("http://example.com/bar").Expect().chain.assertFailed(t)
+
+	e3 := WithConfig(Config{
+		Client:   NewCassetteClient(nil, path, Auto),
+		Reporter: reporter,
+	})
+
+	e3.GET("http://example.com/bar").Expect().chain.assertFailed(t)
+}
+
+func TestCassetteBadFile(t *testing.T) {
+	dir := createCassetteDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassette.json")
+
+	if err := ioutil.WriteFile(path, []byte("bad"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewCassetteClient(nil, path, Replay)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	resp, err := client.Do(req)
+
+	assert.Nil(t, resp)
+	assert.NotNil(t, err)
+}
@@ -1,23 +1,115 @@
 package httpexpect
 
+import (
+	"fmt"
+	"strings"
+)
+
 type chain struct {
-	reporter Reporter
-	failbit  bool
+	reporter      Reporter
+	canonicalizer Canonicalizer
+	testname      string
+	context       string
+	path          string
+	failhooks     []func()
+	fatal         bool
+	failbit       bool
 }
 
 func makeChain(reporter Reporter) chain {
-	return chain{reporter, false}
+	return chain{reporter: reporter}
+}
+
+func makeConfigChain(config Config) chain {
+	return chain{
+		reporter:      config.Reporter,
+		canonicalizer: config.Canonicalizer,
+		testname:      config.TestName,
+	}
 }
 
 func (c *chain) failed() bool {
 	return c.failbit
 }
 
+// enter returns a copy of the chain for a child object, with given path
+// element appended to assertion path, e.g. ".Object" or "[3]".
+func (c *chain) enter(format string, args ...interface{}) chain {
+	child := *c
+	elem := fmt.Sprintf(format, args...)
+	if child.path == "" {
+		elem = strings.TrimPrefix(elem, ".")
+	}
+	child.path += elem
+	return child
+}
+
+// alias replaces assertion path with given name; paths of child
+// objects are then built relative to it.
+func (c *chain) alias(name string) {
+	c.path = name
+}
+
 func (c *chain) fail(message string, args ...interface{}) {
+	c.report(Failure{}, message, args...)
+}
+
+// failValues is like fail, but also records name of failed assertion
+// and compared values, for reporters implementing StructuredReporter.
+func (c *chain) failValues(assertion string, expected, actual interface{},
+	message string, args ...interface{}) {
+	c.report(Failure{
+		Assertion: assertion,
+		Expected:  expected,
+		Actual:    actual,
+	}, message, args...)
+}
+
+func (c *chain) report(failure Failure, message string, args ...interface{}) {
 	if c.failbit {
 		return
 	}
 	c.failbit = true
+
+	for _, hook := range c.failhooks {
+		hook()
+	}
+
+	if c.path != "" {
+		if !strings.HasPrefix(message, "\n") {
+			message = "\n" + message
+		}
+		message = "\nassertion path:\n  " +
+			strings.Replace(c.path, "%", "%%", -1) + "\n" + message
+	}
+
+	if c.testname != "" {
+		if !strings.HasPrefix(message, "\n") {
+			message = "\n" + message
+		}
+		message = "\ntest name:\n  " +
+			strings.Replace(c.testname, "%", "%%", -1) + "\n" + message
+	}
+
+	if r, ok := c.reporter.(StructuredReporter); ok {
+		failure.Message = fmt.Sprintf(message, args...)
+		failure.Format = message
+		failure.Args = args
+		failure.TestName = c.testname
+		failure.Context = c.context
+		failure.Fatal = c.fatal
+		failure.Path = c.path
+		r.Report(failure)
+		return
+	}
+
+	if c.fatal {
+		if r, ok := c.reporter.(FatalReporter); ok {
+			r.Fatalf(message, args...)
+			return
+		}
+	}
+
 	c.reporter.Errorf(message, args...)
 }
 
@@ -2,7 +2,9 @@ package httpexpect
 
 import (
 	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestChainFail(t *testing.T) {
@@ -61,3 +63,218 @@ func TestChainReport(t *testing.T) {
 	chain.assertOK(r2)
 	assert.True(t, r2.reported)
 }
+
+func TestChainPath(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	c1 := chain.enter(".JSON")
+	c2 := c1.enter(".Object")
+	c3 := c2.enter("[%q]", "foo")
+	c4 := c3.enter(".Array")
+	c5 := c4.enter("[%d]", 3)
+
+	assert.Equal(t, "", chain.path)
+	assert.Equal(t, "JSON", c1.path)
+	assert.Equal(t, "JSON.Object", c2.path)
+	assert.Equal(t, `JSON.Object["foo"]`, c3.path)
+	assert.Equal(t, `JSON.Object["foo"].Array`, c4.path)
+	assert.Equal(t, `JSON.Object["foo"].Array[3]`, c5.path)
+
+	c5.fail("fail")
+
+	assert.True(t, c5.failed())
+	assert.False(t, c4.failed())
+}
+
+func TestChainPathMessage(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	value := NewValue(collector, map[string]interface{}{
+		"foo%": []interface{}{1, 2, "bar"},
+	})
+
+	value.Object().Value("foo%").Array().Element(2).String().Equal("baz")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 1, len(failures))
+
+	assert.Equal(t, `Object["foo%"].Array[2].String`, failures[0].Path)
+	assert.Contains(t, failures[0].Message,
+		"\nassertion path:\n  "+`Object["foo%"].Array[2].String`+"\n\n")
+	assert.Contains(t, failures[0].Message, "baz")
+}
+
+func TestChainPathWebsocket(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler())
+	defer server.Close()
+
+	config := Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	}
+
+	e := WithConfig(config)
+
+	resp := e.GET("/").WithWebsocketUpgrade().Expect()
+
+	ws := resp.Websocket().WithReadTimeout(time.Second)
+	defer ws.Close()
+
+	assert.Equal(t, "Websocket", ws.chain.path)
+	assert.Equal(t, "Websocket.Subprotocol", ws.Subprotocol().chain.path)
+
+	ws.WriteText(`"hello"`)
+	msg := ws.Expect()
+
+	assert.Equal(t, "Websocket.Expect()", msg.chain.path)
+	assert.Equal(t, "Websocket.Expect().Body", msg.Body().chain.path)
+	assert.Equal(t, "Websocket.Expect().Text", msg.Text().chain.path)
+	assert.Equal(t, "Websocket.Expect().Binary", msg.Binary().chain.path)
+	assert.Equal(t, "Websocket.Expect().JSON", msg.JSON().chain.path)
+}
+
+func TestChainAlias(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	value := NewValue(collector, map[string]interface{}{
+		"emails": []interface{}{"foo"},
+	})
+
+	email := value.Object().Value("emails").Array().First().Alias("primaryEmail")
+
+	email.String().Contains("@")
+	email.String().Trim().Equal("bar")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 2, len(failures))
+
+	assert.Equal(t, "primaryEmail.String", failures[0].Path)
+	assert.Contains(t, failures[0].Message,
+		"\nassertion path:\n  primaryEmail.String\n\n")
+
+	assert.Equal(t, "primaryEmail.String.Trim", failures[1].Path)
+
+	value.Object().Value("emails").Array().First().String().Contains("@")
+
+	failures = collector.Failures()
+
+	assert.Equal(t, 3, len(failures))
+	assert.Equal(t, `Object["emails"].Array[0].String`, failures[2].Path)
+}
+
+func TestChainAliasTypes(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	assert.Equal(t, "a", NewValue(reporter, 1).Alias("a").chain.path)
+	assert.Equal(t, "a", NewObject(reporter, nil).Alias("a").chain.path)
+	assert.Equal(t, "a", NewArray(reporter, nil).Alias("a").chain.path)
+	assert.Equal(t, "a", NewString(reporter, "").Alias("a").chain.path)
+	assert.Equal(t, "a", NewNumber(reporter, 0).Alias("a").chain.path)
+	assert.Equal(t, "a", NewBoolean(reporter, true).Alias("a").chain.path)
+
+	value := NewValue(reporter, 1)
+	value.Alias("a")
+	assert.Equal(t, "", value.chain.path)
+}
+
+func TestChainTestName(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	config := Config{
+		TestName: "my test%",
+		Reporter: collector,
+	}
+
+	chain := makeConfigChain(config)
+
+	c1 := chain.enter(".JSON")
+
+	c1.fail("fail")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 1, len(failures))
+
+	assert.Equal(t, "my test%", failures[0].TestName)
+	assert.Equal(t,
+		"\ntest name:\n  my test%\n\nassertion path:\n  JSON\n\nfail",
+		failures[0].Message)
+}
+
+func TestChainStructuredReporter(t *testing.T) {
+	reporter := &mockStructuredReporter{}
+
+	NewNumber(reporter, 1).Alias("count").Equal(2)
+	NewString(reporter, "foo").Require().Contains("bar")
+	NewObject(reporter, map[string]interface{}{"a": 1}).
+		Equal(map[string]interface{}{"a": 2})
+
+	assert.Equal(t, 0, reporter.errors)
+	assert.Equal(t, 3, len(reporter.failures))
+
+	f := reporter.failures[0]
+	assert.Equal(t, "Equal", f.Assertion)
+	assert.Equal(t, 2.0, f.Expected)
+	assert.Equal(t, 1.0, f.Actual)
+	assert.Equal(t, "count", f.Path)
+	assert.False(t, f.Fatal)
+	assert.Contains(t, f.Message, "expected number == 2, but got 1")
+
+	f = reporter.failures[1]
+	assert.Equal(t, "", f.Assertion)
+	assert.Nil(t, f.Expected)
+	assert.Nil(t, f.Actual)
+	assert.True(t, f.Fatal)
+	assert.Contains(t, f.Message, "bar")
+
+	f = reporter.failures[2]
+	assert.Equal(t, "Equal", f.Assertion)
+	assert.Equal(t, map[string]interface{}{"a": 2.0}, f.Expected)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, f.Actual)
+}
+
+func TestChainStructuredReporterAssertions(t *testing.T) {
+	reporter := &mockStructuredReporter{}
+
+	obj := map[string]interface{}{"a": 1}
+
+	NewValue(reporter, 1).Equal(2)
+	NewValue(reporter, 1).NotEqual(1)
+	NewObject(reporter, obj).Equal(map[string]interface{}{})
+	NewObject(reporter, obj).NotEqual(obj)
+	NewObject(reporter, obj).EqualIgnoring([]string{"b"}, map[string]interface{}{"a": 2})
+	NewArray(reporter, []interface{}{1}).Equal([]interface{}{})
+	NewArray(reporter, []interface{}{1}).NotEqual([]interface{}{1})
+	NewArray(reporter, []interface{}{1}).EqualUnordered(2)
+	NewArray(reporter, []interface{}{1}).ContainsExactly(2)
+	NewString(reporter, "a").Equal("b")
+	NewString(reporter, "a").NotEqual("a")
+	NewNumber(reporter, 1).Equal(2)
+	NewNumber(reporter, 1).NotEqual(1)
+	NewNumber(reporter, 1).EqualDelta(2, 0.1)
+	NewNumber(reporter, 1).NotEqualDelta(1, 0.1)
+	NewBoolean(reporter, true).Equal(false)
+	NewBoolean(reporter, true).NotEqual(true)
+
+	expected := []string{
+		"Equal", "NotEqual",
+		"Equal", "NotEqual", "EqualIgnoring",
+		"Equal", "NotEqual", "EqualUnordered", "ContainsExactly",
+		"Equal", "NotEqual",
+		"Equal", "NotEqual", "EqualDelta", "NotEqualDelta",
+		"Equal", "NotEqual",
+	}
+
+	var actual []string
+	for _, f := range reporter.failures {
+		actual = append(actual, f.Assertion)
+	}
+
+	assert.Equal(t, expected, actual)
+
+	NewString(reporter, "a").Contains("b")
+
+	assert.Equal(t, "", reporter.failures[len(reporter.failures)-1].Assertion)
+}
@@ -0,0 +1,149 @@
+package httpexpect
+
+import (
+	"net/http"
+	"time"
+)
+
+// Cookie provides methods to inspect attached http.Cookie value.
+type Cookie struct {
+	chain chain
+	value *http.Cookie
+}
+
+// NewCookie returns a new Cookie object given a reporter used to report
+// failures and cookie value to be inspected.
+//
+// reporter and value should not be nil.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Domain().Equal("example.com")
+//  cookie.Path().Equal("/")
+//  cookie.Expires().InRange(time.Now(), time.Now().Add(time.Hour * 24))
+func NewCookie(reporter Reporter, value *http.Cookie) *Cookie {
+	chain := makeChain(reporter)
+	if value == nil {
+		chain.fail("expected non-nil cookie")
+	}
+	return &Cookie{chain, value}
+}
+
+// Raw returns underlying http.Cookie value attached to Cookie.
+// This is the value originally passed to NewCookie.
+//
+// Example:
+//  cookie := NewCookie(t, c)
+//  assert.Equal(t, c, cookie.Raw())
+func (c *Cookie) Raw() *http.Cookie {
+	return c.value
+}
+
+// Name returns a new String object that may be used to inspect
+// cookie name.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Name().Equal("session")
+func (c *Cookie) Name() *String {
+	if c.chain.failed() {
+		return &String{c.chain.enter(".Name"), ""}
+	}
+	return &String{c.chain.enter(".Name"), c.value.Name}
+}
+
+// Value returns a new String object that may be used to inspect
+// cookie value.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Value().Equal("gH6z7Y")
+func (c *Cookie) Value() *String {
+	if c.chain.failed() {
+		return &String{c.chain.enter(".Value"), ""}
+	}
+	return &String{c.chain.enter(".Value"), c.value.Value}
+}
+
+// Domain returns a new String object that may be used to inspect
+// cookie domain.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Domain().Equal("example.com")
+func (c *Cookie) Domain() *String {
+	if c.chain.failed() {
+		return &String{c.chain.enter(".Domain"), ""}
+	}
+	return &String{c.chain.enter(".Domain"), c.value.Domain}
+}
+
+// Path returns a new String object that may be used to inspect
+// cookie path.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Path().Equal("/foo")
+func (c *Cookie) Path() *String {
+	if c.chain.failed() {
+		return &String{c.chain.enter(".Path"), ""}
+	}
+	return &String{c.chain.enter(".Path"), c.value.Path}
+}
+
+// Expires returns a new DateTime object that may be used to inspect
+// cookie expiration date.
+//
+// If cookie has no expiration date, zero time is used.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Expires().InRange(time.Now(), time.Now().Add(time.Hour * 24))
+func (c *Cookie) Expires() *DateTime {
+	if c.chain.failed() {
+		return &DateTime{c.chain.enter(".Expires"), time.Time{}}
+	}
+	return &DateTime{c.chain.enter(".Expires"), c.value.Expires}
+}
+
+// MaxAge returns a new Number object that may be used to inspect
+// cookie "Max-Age" attribute, in seconds.
+//
+// Like in http.Cookie, zero means that Max-Age is not specified, and
+// negative value means that Max-Age attribute is "0".
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.MaxAge().Equal(3600)
+func (c *Cookie) MaxAge() *Number {
+	if c.chain.failed() {
+		return &Number{c.chain.enter(".MaxAge"), 0, 0}
+	}
+	return &Number{c.chain.enter(".MaxAge"), float64(c.value.MaxAge), 0}
+}
+
+// Secure returns a new Boolean object that may be used to inspect
+// cookie "Secure" attribute.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.Secure().True()
+func (c *Cookie) Secure() *Boolean {
+	if c.chain.failed() {
+		return &Boolean{c.chain.enter(".Secure"), false}
+	}
+	return &Boolean{c.chain.enter(".Secure"), c.value.Secure}
+}
+
+// HttpOnly returns a new Boolean object that may be used to inspect
+// cookie "HttpOnly" attribute.
+//
+// Example:
+//  cookie := NewCookie(t, &http.Cookie{...})
+//  cookie.HttpOnly().True()
+func (c *Cookie) HttpOnly() *Boolean {
+	if c.chain.failed() {
+		return &Boolean{c.chain.enter(".HttpOnly"), false}
+	}
+	return &Boolean{c.chain.enter(".HttpOnly"), c.value.HttpOnly}
+}
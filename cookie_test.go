@@ -0,0 +1,76 @@
+package httpexpect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCookieFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	value := &Cookie{chain, nil}
+
+	value.chain.assertFailed(t)
+
+	value.Name().chain.assertFailed(t)
+	value.Value().chain.assertFailed(t)
+	value.Domain().chain.assertFailed(t)
+	value.Path().chain.assertFailed(t)
+	value.Expires().chain.assertFailed(t)
+	value.MaxAge().chain.assertFailed(t)
+	value.Secure().chain.assertFailed(t)
+	value.HttpOnly().chain.assertFailed(t)
+}
+
+func TestCookieNil(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewCookie(reporter, nil)
+
+	value.chain.assertFailed(t)
+
+	assert.True(t, value.Raw() == nil)
+}
+
+func TestCookieGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cookie := &http.Cookie{
+		Name:     "name",
+		Value:    "value",
+		Domain:   "example.com",
+		Path:     "/path",
+		Expires:  time.Unix(1234, 0),
+		MaxAge:   3600,
+		Secure:   true,
+		HttpOnly: false,
+	}
+
+	value := NewCookie(reporter, cookie)
+
+	assert.Equal(t, cookie, value.Raw())
+
+	value.Name().Equal("name")
+	value.Value().Equal("value")
+	value.Domain().Equal("example.com")
+	value.Path().Equal("/path")
+	value.Expires().Equal(time.Unix(1234, 0))
+	value.MaxAge().Equal(3600)
+	value.Secure().True()
+	value.HttpOnly().False()
+
+	value.chain.assertOK(t)
+
+	value.Name().Equal("").chain.assertFailed(t)
+	value.Value().Equal("").chain.assertFailed(t)
+	value.Domain().Equal("").chain.assertFailed(t)
+	value.Path().Equal("").chain.assertFailed(t)
+	value.Expires().Equal(time.Unix(4321, 0)).chain.assertFailed(t)
+	value.MaxAge().Equal(0).chain.assertFailed(t)
+	value.Secure().False().chain.assertFailed(t)
+	value.HttpOnly().True().chain.assertFailed(t)
+}
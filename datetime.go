@@ -0,0 +1,180 @@
+package httpexpect
+
+import (
+	"time"
+)
+
+// DateTime provides methods to inspect attached time.Time value.
+type DateTime struct {
+	chain chain
+	value time.Time
+}
+
+// NewDateTime returns a new DateTime object given a reporter used to report
+// failures and time.Time value to be inspected.
+//
+// reporter should not be nil.
+//
+// Example:
+//  dt := NewDateTime(t, time.Now())
+//  dt.Le(time.Now())
+//
+//  time.Sleep(time.Second)
+//  dt.Lt(time.Now())
+func NewDateTime(reporter Reporter, value time.Time) *DateTime {
+	return &DateTime{makeChain(reporter), value}
+}
+
+// Raw returns underlying time.Time value attached to DateTime.
+// This is the value originally passed to NewDateTime.
+//
+// Example:
+//  dt := NewDateTime(t, timestamp)
+//  assert.Equal(t, timestamp, dt.Raw())
+func (dt *DateTime) Raw() time.Time {
+	return dt.value
+}
+
+// Equal succeedes if DateTime is equal to given value.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 1))
+//  dt.Equal(time.Unix(0, 1))
+func (dt *DateTime) Equal(value time.Time) *DateTime {
+	if !dt.value.Equal(value) {
+		dt.chain.fail("\nexpected datetime equal to:\n  %s\n\nbut got:\n  %s",
+			value, dt.value)
+	}
+	return dt
+}
+
+// NotEqual succeedes if DateTime is not equal to given value.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 1))
+//  dt.NotEqual(time.Unix(0, 2))
+func (dt *DateTime) NotEqual(value time.Time) *DateTime {
+	if dt.value.Equal(value) {
+		dt.chain.fail("\nexpected datetime NOT equal to:\n  %s", value)
+	}
+	return dt
+}
+
+// Gt succeedes if DateTime is greater than given value.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 2))
+//  dt.Gt(time.Unix(0, 1))
+func (dt *DateTime) Gt(value time.Time) *DateTime {
+	if !dt.value.After(value) {
+		dt.chain.fail("\nexpected datetime > than:\n  %s\n\nbut got:\n  %s",
+			value, dt.value)
+	}
+	return dt
+}
+
+// Ge succeedes if DateTime is greater than or equal to given value.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 2))
+//  dt.Ge(time.Unix(0, 1))
+func (dt *DateTime) Ge(value time.Time) *DateTime {
+	if !(dt.value.After(value) || dt.value.Equal(value)) {
+		dt.chain.fail("\nexpected datetime >= than:\n  %s\n\nbut got:\n  %s",
+			value, dt.value)
+	}
+	return dt
+}
+
+// Lt succeedes if DateTime is lesser than given value.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 1))
+//  dt.Lt(time.Unix(0, 2))
+func (dt *DateTime) Lt(value time.Time) *DateTime {
+	if !dt.value.Before(value) {
+		dt.chain.fail("\nexpected datetime < than:\n  %s\n\nbut got:\n  %s",
+			value, dt.value)
+	}
+	return dt
+}
+
+// Le succeedes if DateTime is lesser than or equal to given value.
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 1))
+//  dt.Le(time.Unix(0, 2))
+func (dt *DateTime) Le(value time.Time) *DateTime {
+	if !(dt.value.Before(value) || dt.value.Equal(value)) {
+		dt.chain.fail("\nexpected datetime <= than:\n  %s\n\nbut got:\n  %s",
+			value, dt.value)
+	}
+	return dt
+}
+
+// InRange succeedes if DateTime is in given range [min; max].
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 2))
+//  dt.InRange(time.Unix(0, 1), time.Unix(0, 3))
+//  dt.InRange(time.Unix(0, 2), time.Unix(0, 2))
+func (dt *DateTime) InRange(min, max time.Time) *DateTime {
+	if !((dt.value.After(min) || dt.value.Equal(min)) &&
+		(dt.value.Before(max) || dt.value.Equal(max))) {
+		dt.chain.fail(
+			"\nexpected datetime in range:\n  min: %s\n  max: %s\n\nbut got: %s",
+			min, max, dt.value)
+	}
+	return dt
+}
+
+// Zone returns a new String object that may be used to inspect time zone
+// abbreviation of DateTime, e.g. "UTC" or "CET".
+//
+// Example:
+//  dt := NewDateTime(t, time.Unix(0, 0).UTC())
+//  dt.Zone().Equal("UTC")
+func (dt *DateTime) Zone() *String {
+	zone, _ := dt.value.Zone()
+	return &String{dt.chain.enter(".Zone"), zone}
+}
+
+// Year returns a new Number object that may be used to inspect year
+// of DateTime.
+//
+// Example:
+//  dt := NewDateTime(t, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC))
+//  dt.Year().Equal(2017)
+func (dt *DateTime) Year() *Number {
+	return &Number{dt.chain.enter(".Year"), float64(dt.value.Year()), 0}
+}
+
+// Month returns a new Number object that may be used to inspect month
+// of DateTime, in range [1; 12].
+//
+// Example:
+//  dt := NewDateTime(t, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC))
+//  dt.Month().Equal(time.January)
+func (dt *DateTime) Month() *Number {
+	return &Number{dt.chain.enter(".Month"), float64(dt.value.Month()), 0}
+}
+
+// Day returns a new Number object that may be used to inspect day of month
+// of DateTime, in range [1; 31].
+//
+// Example:
+//  dt := NewDateTime(t, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC))
+//  dt.Day().Equal(2)
+func (dt *DateTime) Day() *Number {
+	return &Number{dt.chain.enter(".Day"), float64(dt.value.Day()), 0}
+}
+
+// Weekday returns a new Number object that may be used to inspect day of
+// week of DateTime, in range [0; 6], where 0 is Sunday.
+//
+// Example:
+//  dt := NewDateTime(t, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC))
+//  dt.Weekday().Equal(time.Monday)
+func (dt *DateTime) Weekday() *Number {
+	return &Number{dt.chain.enter(".Weekday"), float64(dt.value.Weekday()), 0}
+}
@@ -0,0 +1,157 @@
+package httpexpect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDateTimeFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	value := &DateTime{chain, time.Unix(0, 0)}
+
+	value.chain.assertFailed(t)
+
+	value.Equal(time.Unix(0, 0))
+	value.NotEqual(time.Unix(0, 0))
+	value.Gt(time.Unix(0, 0))
+	value.Ge(time.Unix(0, 0))
+	value.Lt(time.Unix(0, 0))
+	value.Le(time.Unix(0, 0))
+	value.InRange(time.Unix(0, 0), time.Unix(0, 0))
+
+	value.Zone().chain.assertFailed(t)
+	value.Year().chain.assertFailed(t)
+	value.Month().chain.assertFailed(t)
+	value.Day().chain.assertFailed(t)
+	value.Weekday().chain.assertFailed(t)
+}
+
+func TestDateTimeEqual(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Unix(0, 1234))
+
+	assert.True(t, time.Unix(0, 1234).Equal(value.Raw()))
+
+	value.Equal(time.Unix(0, 1234))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Equal(time.Unix(0, 4321))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqual(time.Unix(0, 4321))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotEqual(time.Unix(0, 1234))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDateTimeGreater(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Unix(0, 1234))
+
+	value.Gt(time.Unix(0, 1234-1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Gt(time.Unix(0, 1234))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Ge(time.Unix(0, 1234-1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Ge(time.Unix(0, 1234))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Ge(time.Unix(0, 1234+1))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDateTimeLesser(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Unix(0, 1234))
+
+	value.Lt(time.Unix(0, 1234+1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Lt(time.Unix(0, 1234))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Le(time.Unix(0, 1234+1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Le(time.Unix(0, 1234))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Le(time.Unix(0, 1234-1))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDateTimeInRange(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewDateTime(reporter, time.Unix(0, 1234))
+
+	value.InRange(time.Unix(0, 1234), time.Unix(0, 1234))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRange(time.Unix(0, 1234-1), time.Unix(0, 1234))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRange(time.Unix(0, 1234), time.Unix(0, 1234+1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.InRange(time.Unix(0, 1234+1), time.Unix(0, 1234+2))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRange(time.Unix(0, 1234-2), time.Unix(0, 1234-1))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRange(time.Unix(0, 1234+1), time.Unix(0, 1234-1))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestDateTimeGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	loc := time.FixedZone("XYZ", 3600)
+
+	value := NewDateTime(reporter, time.Date(2017, 3, 5, 10, 20, 30, 0, loc))
+
+	value.Zone().Equal("XYZ")
+	value.Year().Equal(2017)
+	value.Month().Equal(time.March)
+	value.Day().Equal(5)
+	value.Weekday().Equal(time.Sunday)
+
+	value.chain.assertOK(t)
+
+	year := value.Year()
+	year.Equal(2018)
+	year.chain.assertFailed(t)
+}
@@ -43,6 +43,9 @@
 // This is equivalent to subsequently json.Marshal() and json.Unmarshal() the value
 // and currently is implemented so.
 //
+// Conversion of custom types (e.g. time.Time or big.Int) may be tuned by
+// providing Config.Canonicalizer, which is invoked before the JSON round-trip.
+//
 // Failure handling
 //
 // When some check fails, failure is reported. If non-fatal failures are used
@@ -63,18 +66,34 @@
 //
 //  s0.Equal("foo")         // success
 //  s1.Equal("bar")         // this check is ignored because s1 is marked as failed
+//
+// Failure messages for child instances include assertion path of the instance,
+// relative to the root instance, e.g.:
+//  JSON.Object["items"].Array[3].String
 package httpexpect
 
 import (
+	"crypto/tls"
+	"github.com/gorilla/websocket"
 	"net/http"
+	"net/http/cookiejar"
 	"testing"
 	"time"
 )
 
 // Expect is a toplevel object that contains user Config and allows
 // to construct Request objects.
+//
+// Expect may be used concurrently from multiple goroutines, e.g. from
+// parallel subtests, as long as Client, Jar, Reporter, and Printers
+// are safe for concurrent use. http.Client, NewJar, testing.T, and all
+// reporters and printers provided by this package are safe. Expect is
+// never modified after creation; Builder, WithName, and other methods
+// return modified copies. Use Parallel to avoid sharing cookies.
 type Expect struct {
-	config Config
+	config       Config
+	builders     []func(*Request)
+	interceptors []func(*http.Request, *http.Response)
 }
 
 // Config contains various settings.
@@ -91,18 +110,99 @@ type Config struct {
 	// custom implementation.
 	Client Client
 
+	// TLSConfig is used to configure TLS when Client is nil, e.g. to trust
+	// a test CA or to skip certificate verification. May be nil.
+	//
+	// If TLSConfig is set, WithConfig uses http.Client with a copy of
+	// http.DefaultTransport that uses a copy of TLSConfig, instead of
+	// http.DefaultClient. TLSConfig is ignored if Client is set.
+	TLSConfig *tls.Config
+
+	// TestName is prepended to every failure message reported by objects
+	// created from this Config. May be empty.
+	//
+	// Expect.WithName may be used to set it for a derived Expect instance.
+	TestName string
+
+	// Headers are added to every request created from this Config.
+	// May be nil.
+	//
+	// Headers set by Request.WithHeader or Request.WithHeaders replace
+	// values set here. Content-Type set here is treated as if it was set
+	// by Request.WithHeader, i.e. it conflicts with e.g. Request.WithJSON.
+	Headers map[string]string
+
+	// RequestFactory is used to create http.Request for every new
+	// Request, given method and final URL (with BaseURL prepended).
+	// May be nil. If nil, http.Request is constructed directly.
+	//
+	// It may be used, for example, to add headers to every request.
+	RequestFactory RequestFactory
+
+	// WebsocketDialer is used to establish WebSocket connections for
+	// requests with WithWebsocketUpgrade.
+	// Should not be nil if WebSocket requests are used.
+	//
+	// You can use websocket.DefaultDialer or websocket.Dialer, or provide
+	// custom implementation.
+	//
+	// Note that WebSocket connections can't be established using Binder,
+	// since it doesn't open real network connections.
+	WebsocketDialer WebsocketDialer
+
+	// Jar is used to store cookies set by responses and to send them
+	// within subsequent requests to the same host.
+	// May be nil. If nil, cookies are not stored.
+	//
+	// You can use NewJar, or provide custom implementation.
+	//
+	// Note that if Client is http.Client with its own Jar, this field
+	// should be left nil, otherwise cookies would be sent twice.
+	Jar http.CookieJar
+
+	// DisableDecompression disables automatic decompression of response
+	// bodies with "gzip" or "deflate" Content-Encoding.
+	//
+	// Note that http.Client decompresses gzip responses by itself when
+	// it adds "Accept-Encoding" header automatically; in this case,
+	// Content-Encoding header is removed and this option has no effect.
+	DisableDecompression bool
+
+	// EnableTiming enables collecting durations of request round-trip
+	// phases (DNS lookup, connect, TLS handshake, time to first byte)
+	// using net/http/httptrace. See Response.Timing.
+	//
+	// Timings are collected only if Client supports httptrace, like
+	// http.Client does.
+	EnableTiming bool
+
+	// MaxBodySize limits the size of response body, in bytes. If the body
+	// is larger, reading stops after the limit and the failure is reported
+	// instead of buffering the rest of the body. If the body is compressed,
+	// the limit applies to the decompressed body as well.
+	//
+	// Zero means no limit.
+	MaxBodySize int64
+
+	// Canonicalizer is used to convert values to canonical form before
+	// comparison, before the default JSON round-trip conversion.
+	// May be nil. If nil, only the default conversion is used.
+	//
+	// See Canonicalizer type for details.
+	Canonicalizer Canonicalizer
+
 	// Reporter is used to report failures.
 	// Should not be nil.
 	//
 	// You can use AssertReporter, RequireReporter (they use testify),
-	// or testing.T, or provide custom implementation.
+	// FailureCollector, or testing.T, or provide custom implementation.
 	Reporter Reporter
 
 	// Printers are used to print requests and responses.
 	// May be nil.
 	//
-	// You can use CompactPrinter, DebugPrinter, CurlPrinter, or provide
-	// custom implementation.
+	// You can use CompactPrinter, DebugPrinter, JSONPrinter, CurlPrinter,
+	// FailurePrinter, or provide custom implementation.
 	//
 	// You can also use builtin printers with alternative Logger if
 	// you're happy with their format, but want to send logs somewhere
@@ -111,15 +211,65 @@ type Config struct {
 }
 
 // Client is used to send http.Request and receive http.Response.
-// http.Client, Binder, fasthttpexpect.ClientAdapter, fasthttpexpect.Binder
-// implement this interface.
+// http.Client, Binder, RoundTripperClient, fasthttpexpect.ClientAdapter,
+// fasthttpexpect.Binder implement this interface.
 type Client interface {
 	// Do sends request and returns response.
 	Do(*http.Request) (*http.Response, error)
 }
 
+// WebsocketDialer is used to establish WebSocket connection and receive
+// http.Response of handshake result.
+// websocket.Dialer implements this interface.
+//
+// If WebsocketDialer also implements DialContext method with the same
+// signature as websocket.Dialer, it's used instead of Dial, and request
+// context (see Request.WithContext and Request.WithTimeout) is passed to it.
+type WebsocketDialer interface {
+	// Dial establishes new WebSocket connection and returns response
+	// of handshake result.
+	Dial(url string, reqH http.Header) (*websocket.Conn, *http.Response, error)
+}
+
+// Canonicalizer is used to convert values of custom types to canonical form.
+//
+// Canonicalizer is invoked for every value converted to canonical form
+// (see "Value equality" section in package documentation), and also for
+// every element of maps with string keys, slices, and arrays, recursively.
+// It should return either a replacement value, which is then converted
+// using JSON round-trip, or the original value if it doesn't need special
+// handling. If it returns error, failure is reported.
+//
+// Note that struct fields are not traversed; use json.Marshaler for custom
+// struct field types instead.
+//
+// Example:
+//  func(value interface{}) (interface{}, error) {
+//      switch v := value.(type) {
+//      case time.Time:
+//          return v.UTC().Format(time.RFC3339), nil
+//      case *big.Int:
+//          return v.String(), nil
+//      }
+//      return value, nil
+//  }
+type Canonicalizer func(value interface{}) (interface{}, error)
+
+// RequestFactory is used to create http.Request objects.
+//
+// Example:
+//  factory := func(method, url string) (*http.Request, error) {
+//      req, err := http.NewRequest(method, url, nil)
+//      if err == nil {
+//          req.Header.Set("X-Trace-Id", traceID)
+//      }
+//      return req, err
+//  }
+type RequestFactory func(method, url string) (*http.Request, error)
+
 // Printer is used to print requests and responses.
-// CompactPrinter, DebugPrinter, and CurlPrinter implement this interface.
+// CompactPrinter, DebugPrinter, JSONPrinter, CurlPrinter, and FailurePrinter
+// implement this interface.
 type Printer interface {
 	// Request is called before request is sent.
 	Request(*http.Request)
@@ -128,6 +278,22 @@ type Printer interface {
 	Response(*http.Response, time.Duration)
 }
 
+// NamedPrinter is an optional interface that may be implemented by Printer.
+// If printer implements it, NamedRequest and NamedResponse are called
+// instead of Request and Response, and receive the name set by
+// Request.WithName (empty if it wasn't set).
+//
+// CompactPrinter implements this interface.
+type NamedPrinter interface {
+	Printer
+
+	// NamedRequest is called before request is sent.
+	NamedRequest(name string, req *http.Request)
+
+	// NamedResponse is called after response is received.
+	NamedResponse(name string, resp *http.Response, duration time.Duration)
+}
+
 // Logger is used as output backend for Printer.
 // testing.T implements this interface.
 type Logger interface {
@@ -137,13 +303,37 @@ type Logger interface {
 
 // Reporter is used to report failures.
 // testing.T implements this interface. AssertReporter and RequireReporter,
-// also implement this interface using testify.
+// also implement this interface using testify. FailureCollector implements
+// this interface and collects failures. See also StructuredReporter.
 type Reporter interface {
 	// Errorf reports failure.
 	// Allowed to return normally or terminate test using t.FailNow().
 	Errorf(message string, args ...interface{})
 }
 
+// FatalReporter is an optional interface that may be implemented by
+// Reporter. It's used to report failures of objects returned by Require
+// methods. testing.T, AssertReporter, RequireReporter, and FailureCollector
+// implement this interface.
+type FatalReporter interface {
+	Reporter
+
+	// Fatalf reports failure and terminates test using t.FailNow().
+	Fatalf(message string, args ...interface{})
+}
+
+// StructuredReporter is an optional interface that may be implemented by
+// Reporter. If implemented, it's used instead of Errorf and Fatalf, and
+// receives failure details as Failure struct, so that they don't need to
+// be parsed from the message. FailureCollector implements this interface.
+type StructuredReporter interface {
+	Reporter
+
+	// Report reports failure. If failure.Fatal is true, it should
+	// terminate test using t.FailNow().
+	Report(failure Failure)
+}
+
 // New returns a new Expect object.
 //
 // baseURL specifies URL to prepended to all request. My be empty. If non-empty,
@@ -151,6 +341,7 @@ type Reporter interface {
 //
 // New is a shorthand for WithConfig. It uses:
 //  - http.DefaultClient as Client
+//  - websocket.DefaultDialer as WebsocketDialer
 //  - CompactPrinter as Printer with testing.T as Logger
 //  - AssertReporter as Reporter
 //
@@ -172,6 +363,7 @@ func New(t *testing.T, baseURL string) *Expect {
 // WithConfig returns a new Expect object with given config.
 //
 // If Config.Client is nil, http.DefaultClient is used.
+// If Config.WebsocketDialer is nil, websocket.DefaultDialer is used.
 //
 // Example:
 //  func TestAPI(t *testing.T) {
@@ -188,80 +380,257 @@ func New(t *testing.T, baseURL string) *Expect {
 //  }
 func WithConfig(config Config) *Expect {
 	if config.Client == nil {
-		config.Client = http.DefaultClient
+		if config.TLSConfig != nil {
+			transport := cloneTransport(http.DefaultTransport.(*http.Transport))
+			transport.TLSClientConfig = cloneTLSConfig(config.TLSConfig)
+			config.Client = &http.Client{Transport: transport}
+		} else {
+			config.Client = http.DefaultClient
+		}
+	}
+	if config.WebsocketDialer == nil {
+		config.WebsocketDialer = websocket.DefaultDialer
 	}
 	if config.Reporter == nil {
 		panic("config.Reporter is nil")
 	}
-	return &Expect{config}
+	return &Expect{config: config}
+}
+
+// NewJar returns a new http.CookieJar that may be used as Config.Jar.
+//
+// Returned jar is an in-memory cookiejar.Jar without public suffix list.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.org/",
+//      Jar:      httpexpect.NewJar(),
+//      Reporter: httpexpect.NewAssertReporter(t),
+//  })
+func NewJar() http.CookieJar {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		panic(err)
+	}
+	return jar
+}
+
+// Clone returns a copy of Expect instance that doesn't share mutable state
+// with the original one. Config.Printers, Config.Headers, and attached
+// builders are copied, so that changes made to the copy (e.g. by Builder
+// or WithName) don't affect the original instance and vice versa.
+//
+// Other Config fields, like Client, Jar, and Reporter, are shared.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.org")
+//
+//  admin := e.Clone().Builder(func(req *httpexpect.Request) {
+//      req.WithBasicAuth("admin", "secret")
+//  })
+func (e *Expect) Clone() *Expect {
+	ret := *e
+
+	if e.config.Printers != nil {
+		ret.config.Printers = make([]Printer, len(e.config.Printers))
+		copy(ret.config.Printers, e.config.Printers)
+	}
+
+	if e.config.Headers != nil {
+		ret.config.Headers = make(map[string]string, len(e.config.Headers))
+		for k, v := range e.config.Headers {
+			ret.config.Headers[k] = v
+		}
+	}
+
+	if e.builders != nil {
+		ret.builders = make([]func(*Request), len(e.builders))
+		copy(ret.builders, e.builders)
+	}
+
+	if e.interceptors != nil {
+		ret.interceptors = make(
+			[]func(*http.Request, *http.Response), len(e.interceptors))
+		copy(ret.interceptors, e.interceptors)
+	}
+
+	return &ret
+}
+
+// Parallel returns a copy of Expect instance intended for use in a
+// parallel subtest. Like Clone, it copies Config.Printers, Config.Headers,
+// builders, and interceptors. In addition, if Config.Jar is non-nil, it's
+// replaced with a new empty jar created by NewJar, so that cookies are
+// not shared between subtests.
+//
+// Note that cookie jar of Config.Client, if any, is still shared.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.org",
+//      Reporter: httpexpect.NewAssertReporter(t),
+//      Jar:      httpexpect.NewJar(),
+//  })
+//
+//  for _, user := range users {
+//      user := user
+//      t.Run(user, func(t *testing.T) {
+//          t.Parallel()
+//          e := e.Parallel()
+//          e.POST("/login").WithFormField("user", user).
+//              Expect().
+//              Status(http.StatusOK)
+//      })
+//  }
+func (e *Expect) Parallel() *Expect {
+	ret := e.Clone()
+	if ret.config.Jar != nil {
+		ret.config.Jar = NewJar()
+	}
+	return ret
+}
+
+// Builder returns a copy of Expect instance with given builder attached
+// to it. Returned copy contains all previously attached builders plus
+// a new one. Builders are invoked from Request method, after the request
+// is constructed, in the order they were attached.
+//
+// Original Expect instance is not modified.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.org")
+//
+//  auth := e.Builder(func(req *httpexpect.Request) {
+//      req.WithBearer(token)
+//  })
+//
+//  auth.GET("/restricted").
+//     Expect().
+//     Status(http.StatusOK)
+func (e *Expect) Builder(builder func(*Request)) *Expect {
+	ret := e.Clone()
+	ret.builders = append(ret.builders, builder)
+	return ret
+}
+
+// AddInterceptor adds given interceptor to Expect instance.
+//
+// Interceptor is invoked twice for every request sent by this instance,
+// including retries. First, it's invoked with nil response just before the
+// request is sent, and may modify the request, e.g. add headers. Then, it's
+// invoked with the received response, which it should not modify. When
+// there are several interceptors, they are invoked in the order they were
+// added.
+//
+// Requests that were already created are not affected. Copies made by
+// Clone before the call don't get the interceptor, and copies made after
+// it do.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.org")
+//
+//  e.AddInterceptor(func(req *http.Request, resp *http.Response) {
+//      if resp == nil {
+//          req.Header.Set("X-Trace-Id", newTraceID())
+//      }
+//  })
+//
+//  e.GET("/path").
+//     Expect().
+//     Status(http.StatusOK)
+func (e *Expect) AddInterceptor(fn func(*http.Request, *http.Response)) {
+	e.interceptors = append(e.interceptors, fn)
+}
+
+// WithName returns a copy of Expect instance with Config.TestName set to
+// given name. Original Expect instance is not modified.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.org")
+//
+//  t.Run("login", func(t *testing.T) {
+//      e := e.WithName("login")
+//      e.POST("/login").Expect().Status(http.StatusOK)
+//  })
+func (e *Expect) WithName(name string) *Expect {
+	ret := e.Clone()
+	ret.config.TestName = name
+	return ret
 }
 
 // Request is a shorthand for NewRequest(config, method, url, args...).
+// Builders attached with Builder are invoked for returned request, and
+// interceptors attached with AddInterceptor are invoked when it's sent.
 func (e *Expect) Request(method, url string, args ...interface{}) *Request {
-	return NewRequest(e.config, method, url, args...)
+	req := NewRequest(e.config, method, url, args...)
+	req.hooks = e.interceptors
+	for _, builder := range e.builders {
+		builder(req)
+	}
+	return req
 }
 
 // OPTIONS is a shorthand for NewRequest(config, "OPTIONS", url, args...).
 func (e *Expect) OPTIONS(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "OPTIONS", url, args...)
+	return e.Request("OPTIONS", url, args...)
 }
 
 // HEAD is a shorthand for NewRequest(config, "HEAD", url, args...).
 func (e *Expect) HEAD(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "HEAD", url, args...)
+	return e.Request("HEAD", url, args...)
 }
 
 // GET is a shorthand for NewRequest(config, "GET", url, args...).
 func (e *Expect) GET(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "GET", url, args...)
+	return e.Request("GET", url, args...)
 }
 
 // POST is a shorthand for NewRequest(config, "POST", url, args...).
 func (e *Expect) POST(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "POST", url, args...)
+	return e.Request("POST", url, args...)
 }
 
 // PUT is a shorthand for NewRequest(config, "PUT", url, args...).
 func (e *Expect) PUT(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "PUT", url, args...)
+	return e.Request("PUT", url, args...)
 }
 
 // PATCH is a shorthand for NewRequest(config, "PATCH", url, args...).
 func (e *Expect) PATCH(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "PATCH", url, args...)
+	return e.Request("PATCH", url, args...)
 }
 
 // DELETE is a shorthand for NewRequest(config, "DELETE", url, args...).
 func (e *Expect) DELETE(url string, args ...interface{}) *Request {
-	return NewRequest(e.config, "DELETE", url, args...)
+	return e.Request("DELETE", url, args...)
 }
 
 // Value is a shorthand for NewValue(Config.Reporter, value).
 func (e *Expect) Value(value interface{}) *Value {
-	return NewValue(e.config.Reporter, value)
+	return &Value{makeConfigChain(e.config), value}
 }
 
 // Object is a shorthand for NewObject(Config.Reporter, value).
 func (e *Expect) Object(value map[string]interface{}) *Object {
-	return NewObject(e.config.Reporter, value)
+	return makeObject(makeConfigChain(e.config), value)
 }
 
 // Array is a shorthand for NewArray(Config.Reporter, value).
 func (e *Expect) Array(value []interface{}) *Array {
-	return NewArray(e.config.Reporter, value)
+	return makeArray(makeConfigChain(e.config), value)
 }
 
 // String is a shorthand for NewString(Config.Reporter, value).
 func (e *Expect) String(value string) *String {
-	return NewString(e.config.Reporter, value)
+	return &String{makeConfigChain(e.config), value}
 }
 
 // Number is a shorthand for NewNumber(Config.Reporter, value).
 func (e *Expect) Number(value float64) *Number {
-	return NewNumber(e.config.Reporter, value)
+	return &Number{makeConfigChain(e.config), value, 0}
 }
 
 // Boolean is a shorthand for NewBoolean(Config.Reporter, value).
 func (e *Expect) Boolean(value bool) *Boolean {
-	return NewBoolean(e.config.Reporter, value)
+	return &Boolean{makeConfigChain(e.config), value}
 }
@@ -1,10 +1,17 @@
 package httpexpect
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gavv/httpexpect/fasthttpexpect"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
@@ -43,6 +50,260 @@ func TestExpectMethods(t *testing.T) {
 	assert.Equal(t, "DELETE", reqs[7].http.Method)
 }
 
+func TestExpectBuilder(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	e := WithConfig(config)
+
+	var reqs1, reqs2 []*Request
+
+	e1 := e.Builder(func(r *Request) {
+		reqs1 = append(reqs1, r)
+		r.WithHeader("X-First", "1")
+	})
+
+	e2 := e1.Builder(func(r *Request) {
+		reqs2 = append(reqs2, r)
+		r.WithHeader("X-First", "2")
+	})
+
+	r0 := e.GET("/url")
+	r1 := e1.GET("/url")
+	r2 := e2.POST("/url")
+
+	assert.Equal(t, []*Request{r1, r2}, reqs1)
+	assert.Equal(t, []*Request{r2}, reqs2)
+
+	assert.Equal(t, "", r0.http.Header.Get("X-First"))
+	assert.Equal(t, "1", r1.http.Header.Get("X-First"))
+	assert.Equal(t, "2", r2.http.Header.Get("X-First"))
+
+	e3 := e1.Builder(func(r *Request) {
+		r.WithHeader("X-Third", "3")
+	})
+
+	r4 := e2.GET("/url")
+	r5 := e3.GET("/url")
+
+	assert.Equal(t, "", r4.http.Header.Get("X-Third"))
+	assert.Equal(t, "2", r4.http.Header.Get("X-First"))
+	assert.Equal(t, "3", r5.http.Header.Get("X-Third"))
+	assert.Equal(t, "1", r5.http.Header.Get("X-First"))
+}
+
+func TestExpectWithName(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: collector,
+	}
+
+	e1 := WithConfig(config)
+	e2 := e1.WithName("foo")
+
+	e1.GET("/url").Expect().Status(http.StatusNotFound)
+	e2.GET("/url").Expect().Status(http.StatusNotFound)
+	e2.Number(1).Equal(2)
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 3, len(failures))
+
+	assert.Equal(t, "", failures[0].TestName)
+	assert.False(t, strings.Contains(failures[0].Message, "test name:"))
+
+	assert.Equal(t, "foo", failures[1].TestName)
+	assert.Contains(t, failures[1].Message, "\ntest name:\n  foo\n")
+
+	assert.Equal(t, "foo", failures[2].TestName)
+	assert.Contains(t, failures[2].Message, "\ntest name:\n  foo\n")
+}
+
+func TestExpectClone(t *testing.T) {
+	printer := &mockNamedPrinter{}
+
+	e1 := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: NewAssertReporter(t),
+		Headers:  map[string]string{"X-Foo": "foo"},
+		Printers: []Printer{printer},
+	})
+
+	e1 = e1.Builder(func(req *Request) {
+		req.WithHeader("X-Bar", "bar")
+	})
+
+	e2 := e1.Clone()
+
+	assert.Equal(t, e1.config.Headers, e2.config.Headers)
+	assert.Equal(t, e1.config.Printers, e2.config.Printers)
+	assert.Equal(t, len(e1.builders), len(e2.builders))
+
+	e2.config.Headers["X-Foo"] = "changed"
+	e2.config.Printers[0] = &mockNamedPrinter{}
+	e2.builders[0] = func(req *Request) {}
+
+	assert.Equal(t, "foo", e1.config.Headers["X-Foo"])
+	assert.True(t, e1.config.Printers[0] == printer)
+
+	req := e1.GET("/url")
+	assert.Equal(t, "foo", req.http.Header.Get("X-Foo"))
+	assert.Equal(t, "bar", req.http.Header.Get("X-Bar"))
+
+	e3 := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: NewAssertReporter(t),
+	})
+
+	e4 := e3.Clone()
+
+	assert.Nil(t, e4.config.Headers)
+	assert.Nil(t, e4.config.Printers)
+	assert.Nil(t, e4.builders)
+}
+
+func TestExpectParallel(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/set", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "id", Value: r.URL.Query().Get("id")})
+	})
+	mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("id"); err == nil {
+			w.Write([]byte(c.Value))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Jar:      NewJar(),
+		Printers: []Printer{
+			NewCompactPrinter(t),
+			NewFailurePrinter(t, true),
+		},
+	})
+
+	e.GET("/set").WithQuery("id", "root").Expect().Status(http.StatusOK)
+
+	p := e.Parallel()
+	assert.False(t, p.config.Jar == e.config.Jar)
+
+	p.GET("/get").Expect().Body().Empty()
+	e.GET("/get").Expect().Body().Equal("root")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			id := strconv.Itoa(i)
+
+			p := e.Parallel().Builder(func(req *Request) {
+				req.WithHeader("X-Id", id)
+			})
+
+			p.GET("/set").WithQuery("id", id).Expect().Status(http.StatusOK)
+			p.GET("/get").Expect().Body().Equal(id)
+
+			e.GET("/get").Expect().Status(http.StatusOK)
+		}(i)
+	}
+
+	wg.Wait()
+
+	e.GET("/get").Expect().Body().Equal("root")
+
+	noJar := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+	})
+
+	assert.Nil(t, noJar.Parallel().config.Jar)
+}
+
+func TestExpectInterceptors(t *testing.T) {
+	client := &mockClient{}
+
+	e := WithConfig(Config{
+		Client:   client,
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/url").Expect()
+
+	var calls []string
+
+	e.AddInterceptor(func(req *http.Request, resp *http.Response) {
+		if resp == nil {
+			calls = append(calls, "req1")
+			req.Header.Set("X-Trace", "123")
+		} else {
+			calls = append(calls, "resp1")
+		}
+	})
+
+	e1 := e.Clone()
+
+	e.AddInterceptor(func(req *http.Request, resp *http.Response) {
+		if resp == nil {
+			calls = append(calls, "req2")
+		} else {
+			calls = append(calls, "resp2 "+resp.Header.Get("X-Trace"))
+		}
+	})
+
+	e.GET("/url").Expect().Header("X-Trace").Equal("123")
+
+	assert.Equal(t, []string{"req1", "req2", "resp1", "resp2 123"}, calls)
+	assert.Equal(t, "123", client.req.Header.Get("X-Trace"))
+
+	calls = nil
+
+	e1.GET("/url").Expect()
+
+	assert.Equal(t, []string{"req1", "resp1"}, calls)
+	assert.Equal(t, 1, len(e1.interceptors))
+	assert.Equal(t, 2, len(e.interceptors))
+}
+
+func TestExpectInterceptorsPending(t *testing.T) {
+	client := &mockClient{}
+
+	e := WithConfig(Config{
+		Client:   client,
+		Reporter: NewAssertReporter(t),
+	})
+
+	req := e.GET("/url")
+
+	called := false
+
+	e.AddInterceptor(func(req *http.Request, resp *http.Response) {
+		called = true
+	})
+
+	req.Expect()
+
+	assert.False(t, called)
+
+	e.GET("/url").Expect()
+
+	assert.True(t, called)
+}
+
 func TestExpectValue(t *testing.T) {
 	client := &mockClient{}
 
@@ -186,6 +447,34 @@ func testHandler(e *Expect) {
 		Status(http.StatusNoContent).Body().Empty()
 }
 
+func TestExpectCanonicalizer(t *testing.T) {
+	client := &mockClient{}
+
+	e := WithConfig(Config{
+		Client:   client,
+		Reporter: NewAssertReporter(t),
+		Canonicalizer: func(value interface{}) (interface{}, error) {
+			if tm, ok := value.(time.Time); ok {
+				return tm.UTC().Format(time.RFC3339), nil
+			}
+			return value, nil
+		},
+	})
+
+	tm := time.Date(2016, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+
+	e.Object(map[string]interface{}{"time": tm}).
+		ValueEqual("time", "2016-01-02T02:04:05Z")
+
+	e.Array([]interface{}{tm}).
+		Contains("2016-01-02T02:04:05Z")
+
+	e.POST("/").
+		WithJSON(map[string]interface{}{"time": "2016-01-02T02:04:05Z"}).
+		Expect().
+		JSON().Object().ValueEqual("time", tm.UTC())
+}
+
 func TestExpectLiveDefault(t *testing.T) {
 	handler := createHandler()
 
@@ -251,6 +540,205 @@ func TestExpectBinderStandard(t *testing.T) {
 	}))
 }
 
+func createCookieHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/login", func(w http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(w, &http.Cookie{
+			Name:  "session",
+			Value: "secret",
+			Path:  "/",
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		if err != nil || c.Value != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+func TestExpectJar(t *testing.T) {
+	handler := createCookieHandler()
+
+	e1 := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewBinder(handler),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e1.GET("/login").Expect().Status(http.StatusNoContent)
+	e1.GET("/private").Expect().Status(http.StatusForbidden)
+
+	e2 := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewBinder(handler),
+		Jar:      NewJar(),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e2.GET("/private").Expect().Status(http.StatusForbidden)
+	e2.GET("/login").Expect().Status(http.StatusNoContent)
+	e2.GET("/private").Expect().Status(http.StatusNoContent)
+}
+
+func TestExpectJarLive(t *testing.T) {
+	server := httptest.NewServer(createCookieHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Jar:      NewJar(),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/login").Expect().Status(http.StatusNoContent)
+	e.GET("/private").Expect().Status(http.StatusNoContent)
+}
+
+func TestExpectLiveContext(t *testing.T) {
+	unblock := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-unblock
+		}))
+	defer server.Close()
+	defer close(unblock)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		cancel()
+	}()
+
+	resp := e.GET("/").WithContext(ctx).Expect()
+	resp.chain.assertFailed(t)
+}
+
+func TestExpectTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	e1 := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	e1.GET("/").Expect().chain.assertFailed(t)
+
+	cert, err := x509.ParseCertificate(server.TLS.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	e2 := WithConfig(Config{
+		BaseURL:   server.URL,
+		TLSConfig: tlsConfig,
+		Reporter:  NewAssertReporter(t),
+	})
+
+	e2.GET("/").Expect().Status(http.StatusOK)
+
+	assert.True(t, e2.config.Client != http.DefaultClient)
+	assert.True(t, http.DefaultTransport.(*http.Transport).TLSClientConfig == nil ||
+		http.DefaultTransport.(*http.Transport).TLSClientConfig.RootCAs != pool)
+
+	e3 := WithConfig(Config{
+		BaseURL:   server.URL,
+		Client:    &http.Client{},
+		TLSConfig: tlsConfig,
+		Reporter:  newMockReporter(t),
+	})
+
+	e3.GET("/").Expect().chain.assertFailed(t)
+}
+
+func TestExpectWebsocketLive(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/ws", createWebsocketHandler())
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+	})
+
+	ws := e.GET("/ws").
+		WithHeader("Sec-WebSocket-Protocol", "echo").
+		WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusSwitchingProtocols).
+		Websocket()
+
+	ws.Subprotocol().Equal("echo")
+
+	ws.WriteText("hello").
+		Expect().Text().Equal("hello")
+
+	ws.WriteJSON([]string{"a", "b"}).
+		Expect().JSON().Array().Elements("a", "b")
+
+	ws.Close()
+
+	e.GET("/plain").
+		WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestExpectWebsocketFailures(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	resp := e.GET("/").WithWebsocketUpgrade().Expect()
+	resp.chain.assertOK(t)
+	resp.Websocket().Close()
+
+	resp = e.GET("/").Expect()
+	resp.Websocket()
+	resp.chain.assertFailed(t)
+
+	resp = e.POST("/").WithWebsocketUpgrade().Expect()
+	resp.chain.assertFailed(t)
+
+	resp = e.GET("/").WithText("hello").WithWebsocketUpgrade().Expect()
+	resp.chain.assertFailed(t)
+
+	resp = e.GET("/").WithWebsocketUpgrade().WithTimeout(time.Nanosecond).Expect()
+	resp.chain.assertFailed(t)
+}
+
 func BenchmarkExpectLiveStandard(b *testing.B) {
 	handler := createHandler()
 
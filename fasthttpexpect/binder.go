@@ -1,19 +1,54 @@
 package fasthttpexpect
 
 import (
+	"crypto/tls"
 	"github.com/valyala/fasthttp"
+	"log"
+	"net"
 	"net/http"
+	"os"
 )
 
 // Binder implements networkless httpexpect.Client attached directly to
 // fasthttp.RequestHandler.
 type Binder struct {
-	handler fasthttp.RequestHandler
+	handler    fasthttp.RequestHandler
+	remoteAddr net.Addr
+	tls        *tls.ConnectionState
 }
 
-// NewBinder returns a new Binder given fasthttp.RequestHandler.
-func NewBinder(handler fasthttp.RequestHandler) *Binder {
-	return &Binder{handler}
+// BinderOption defines an option for NewBinder.
+type BinderOption func(*Binder)
+
+// WithRemoteAddr returns BinderOption that sets address returned by
+// RemoteAddr method of fasthttp.RequestCtx passed to handler.
+func WithRemoteAddr(addr net.Addr) BinderOption {
+	return func(binder *Binder) {
+		binder.remoteAddr = addr
+	}
+}
+
+// WithTLS returns BinderOption that sets connection state returned by
+// TLSConnectionState method of fasthttp.RequestCtx passed to handler.
+// IsTLS method returns true in this case.
+func WithTLS(state *tls.ConnectionState) BinderOption {
+	return func(binder *Binder) {
+		binder.tls = state
+	}
+}
+
+// NewBinder returns a new Binder given fasthttp.RequestHandler and options.
+//
+// Example:
+//  binder := fasthttpexpect.NewBinder(handler,
+//      fasthttpexpect.WithTLS(&tls.ConnectionState{}),
+//      fasthttpexpect.WithRemoteAddr(&net.TCPAddr{IP: net.IPv4(1, 2, 3, 4)}))
+func NewBinder(handler fasthttp.RequestHandler, options ...BinderOption) *Binder {
+	binder := &Binder{handler: handler}
+	for _, option := range options {
+		option(binder)
+	}
+	return binder
 }
 
 // Do implements httpexpect.Client.Do.
@@ -24,7 +59,14 @@ func (binder *Binder) Do(stdreq *http.Request) (*http.Response, error) {
 
 	var ctx fasthttp.RequestCtx
 
-	ctx.Init(&fastreq, nil, nil)
+	if binder.tls != nil {
+		// fasthttp detects TLS by checking if connection provides its state
+		conn := &tlsConn{remoteAddr: binder.remoteAddr, state: *binder.tls}
+		ctx.Init2(conn, log.New(os.Stderr, "", log.LstdFlags), true)
+		fastreq.CopyTo(&ctx.Request)
+	} else {
+		ctx.Init(&fastreq, binder.remoteAddr, nil)
+	}
 
 	if stdreq.Body != nil {
 		ctx.Request.SetBodyStream(stdreq.Body, -1)
@@ -34,3 +76,29 @@ func (binder *Binder) Do(stdreq *http.Request) (*http.Response, error) {
 
 	return convertResponse(stdreq, &ctx.Response), nil
 }
+
+// tlsConn is a fake connection; only its addresses and TLS state are used
+type tlsConn struct {
+	net.Conn
+	remoteAddr net.Addr
+	state      tls.ConnectionState
+}
+
+func (c *tlsConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+func (c *tlsConn) RemoteAddr() net.Addr {
+	if c.remoteAddr == nil {
+		return &net.TCPAddr{}
+	}
+	return c.remoteAddr
+}
+
+func (c *tlsConn) Handshake() error {
+	return nil
+}
+
+func (c *tlsConn) ConnectionState() tls.ConnectionState {
+	return c.state
+}
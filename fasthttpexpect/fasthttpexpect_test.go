@@ -2,9 +2,11 @@ package fasthttpexpect
 
 import (
 	"bytes"
+	"crypto/tls"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"testing"
 )
@@ -69,3 +71,45 @@ func TestBinder(t *testing.T) {
 
 	runTest(t, binder)
 }
+
+func TestBinderOptions(t *testing.T) {
+	var (
+		remoteAddr string
+		isTLS      bool
+		tlsState   *tls.ConnectionState
+	)
+
+	handler := func(ctx *fasthttp.RequestCtx) {
+		remoteAddr = ctx.RemoteAddr().String()
+		isTLS = ctx.IsTLS()
+		tlsState = ctx.TLSConnectionState()
+	}
+
+	req, err := http.NewRequest("GET", "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binder1 := NewBinder(handler,
+		WithRemoteAddr(&net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5678}),
+		WithTLS(&tls.ConnectionState{ServerName: "example.com"}))
+
+	if _, err := binder1.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "1.2.3.4:5678", remoteAddr)
+	assert.True(t, isTLS)
+	if assert.NotNil(t, tlsState) {
+		assert.Equal(t, "example.com", tlsState.ServerName)
+	}
+
+	binder2 := NewBinder(handler)
+
+	if _, err := binder2.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, isTLS)
+	assert.Nil(t, tlsState)
+}
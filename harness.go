@@ -0,0 +1,92 @@
+package httpexpect
+
+import (
+	"fmt"
+)
+
+// Case defines a single request and expectations for its response, for
+// table-driven tests run by Expect.Run.
+//
+// Zero fields are ignored: if Body is nil, request has no body; if Status
+// is zero, status is not checked; if JSON is nil, body is not checked.
+type Case struct {
+	// Name is used to identify the case in failure messages.
+	// May be empty. If empty, only case index is used.
+	Name string
+
+	// Method and Path define request method and URL path, which is
+	// appended to Config.BaseURL.
+	Method string
+	Path   string
+
+	// Headers are added to request using Request.WithHeaders.
+	Headers map[string]string
+
+	// Body is encoded to JSON and sent as request body using
+	// Request.WithJSON.
+	Body interface{}
+
+	// Status is expected response status code.
+	Status int
+
+	// JSON is expected response body. It's compared to response JSON
+	// using Value.Equal, so it may be a map, slice, struct, etc.
+	JSON interface{}
+}
+
+// Run sends a request for every given case and checks its response.
+//
+// Failures are reported with test name identifying the case by its index
+// and name, e.g. "case 1 (create user)". If Config.TestName is set, it's
+// prepended to the case name.
+//
+// Example:
+//  e := httpexpect.New(t, "http://example.org")
+//
+//  e.Run(
+//      httpexpect.Case{
+//          Name:   "list users",
+//          Method: "GET",
+//          Path:   "/users",
+//          Status: http.StatusOK,
+//          JSON:   []interface{}{},
+//      },
+//      httpexpect.Case{
+//          Name:   "create user",
+//          Method: "POST",
+//          Path:   "/users",
+//          Body:   map[string]interface{}{"name": "john"},
+//          Status: http.StatusCreated,
+//      },
+//  )
+func (e *Expect) Run(cases ...Case) {
+	for i, c := range cases {
+		name := fmt.Sprintf("case %d", i)
+		if c.Name != "" {
+			name += " (" + c.Name + ")"
+		}
+		if e.config.TestName != "" {
+			name = e.config.TestName + ": " + name
+		}
+
+		req := e.WithName(name).Request(c.Method, "%s", c.Path)
+
+		if c.Headers != nil {
+			req.WithHeaders(c.Headers)
+		}
+
+		if c.Body != nil {
+			req.WithJSON(c.Body)
+		}
+
+		resp := req.Expect()
+
+		if c.Status != 0 {
+			resp.Status(c.Status)
+		}
+
+		if c.JSON != nil {
+			resp.JSON().Equal(c.JSON)
+		}
+	}
+}
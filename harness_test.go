@@ -0,0 +1,123 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func createHarnessHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`[{"name":"john"}]`))
+
+		case "POST":
+			if r.Header.Get("X-Token") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			var user map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&user)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(user)
+		}
+	})
+
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"path": r.URL.Path})
+	})
+
+	return mux
+}
+
+func TestExpectRun(t *testing.T) {
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewBinder(createHarnessHandler()),
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.Run(
+		Case{
+			Name:   "list",
+			Method: "GET",
+			Path:   "/users",
+			Status: http.StatusOK,
+			JSON:   []interface{}{map[string]interface{}{"name": "john"}},
+		},
+		Case{
+			Method:  "POST",
+			Path:    "/users",
+			Headers: map[string]string{"X-Token": "secret"},
+			Body:    map[string]interface{}{"name": "jane"},
+			Status:  http.StatusCreated,
+			JSON:    map[string]interface{}{"name": "jane"},
+		},
+		Case{
+			Name:   "escaped path",
+			Method: "GET",
+			Path:   "/files/a%20b",
+			Status: http.StatusOK,
+			JSON:   map[string]interface{}{"path": "/files/a b"},
+		},
+	)
+}
+
+func TestExpectRunFailed(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   NewBinder(createHarnessHandler()),
+		Reporter: collector,
+	})
+
+	e.Run(
+		Case{
+			Name:   "list",
+			Method: "GET",
+			Path:   "/users",
+			Status: http.StatusOK,
+		},
+		Case{
+			Name:   "create",
+			Method: "POST",
+			Path:   "/users",
+			Body:   map[string]interface{}{"name": "jane"},
+			Status: http.StatusCreated,
+		},
+		Case{
+			Method: "GET",
+			Path:   "/users",
+			JSON:   []interface{}{},
+		},
+	)
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 2, len(failures))
+	assert.Equal(t, "case 1 (create)", failures[0].TestName)
+	assert.Equal(t, "case 2", failures[1].TestName)
+
+	collector.Reset()
+
+	e.WithName("suite").Run(
+		Case{
+			Method: "GET",
+			Path:   "/users",
+			Status: http.StatusNotFound,
+		},
+	)
+
+	failures = collector.Failures()
+
+	assert.Equal(t, 1, len(failures))
+	assert.Equal(t, "suite: case 0", failures[0].TestName)
+}
@@ -1,14 +1,24 @@
 package httpexpect
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/gavv/gojsondiff"
 	"github.com/gavv/gojsondiff/formatter"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 func canonNumber(chain *chain, number interface{}) (f float64, ok bool) {
+	if chain.canonicalizer != nil {
+		var err error
+		if number, err = chain.canonicalizer(number); err != nil {
+			chain.fail(err.Error())
+			return 0, false
+		}
+	}
 	ok = true
 	defer func() {
 		if err := recover(); err != nil {
@@ -45,6 +55,20 @@ func canonMap(chain *chain, in interface{}) (map[string]interface{}, bool) {
 }
 
 func canonValue(chain *chain, in interface{}) (interface{}, bool) {
+	if chain.canonicalizer != nil {
+		var err error
+		if in, err = applyCanonicalizer(chain.canonicalizer, in); err != nil {
+			chain.fail(err.Error())
+			return nil, false
+		}
+	}
+
+	return canonValueStrict(chain, in)
+}
+
+// canonValueStrict is like canonValue, but doesn't apply canonicalizer,
+// so that only conversions defined by JSON rules are performed.
+func canonValueStrict(chain *chain, in interface{}) (interface{}, bool) {
 	b, err := json.Marshal(in)
 	if err != nil {
 		chain.fail(err.Error())
@@ -60,6 +84,117 @@ func canonValue(chain *chain, in interface{}) (interface{}, bool) {
 	return out, true
 }
 
+// jsonType returns JSON type name of canonical value, i.e. one of
+// "object", "array", "string", "number", "boolean", "null", or empty
+// string if value isn't in canonical form.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+
+	return ""
+}
+
+func parseJSON(chain *chain, literal string) (interface{}, bool) {
+	var out interface{}
+	if err := json.Unmarshal([]byte(literal), &out); err != nil {
+		chain.fail("\ninvalid JSON literal:\n  %s\n\n%s",
+			strconv.Quote(literal), err.Error())
+		return nil, false
+	}
+	return out, true
+}
+
+func decodeValue(chain *chain, in interface{}, target interface{}) {
+	if target == nil {
+		chain.fail("\nunexpected nil target in Decode")
+		return
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		chain.fail(err.Error())
+		return
+	}
+
+	if err := json.Unmarshal(b, target); err != nil {
+		chain.fail("\nexpected value decodable into %T:\n%s\n\nbut got error:\n  %s",
+			target, dumpValue(in), err.Error())
+	}
+}
+
+func applyCanonicalizer(c Canonicalizer, in interface{}) (interface{}, error) {
+	out, err := c(in)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(out)
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return out, nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			e, err := applyCanonicalizer(c, v.MapIndex(k).Interface())
+			if err != nil {
+				return nil, err
+			}
+			m[k.String()] = e
+		}
+		return m, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return out, nil
+		}
+		// keep byte slices as is, since they're encoded to base64 strings
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return out, nil
+		}
+		a := make([]interface{}, v.Len())
+		for i := range a {
+			e, err := applyCanonicalizer(c, v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			a[i] = e
+		}
+		return a, nil
+	}
+
+	return out, nil
+}
+
+// canonType is like jsonType, but converts value to canonical form first.
+// Returns empty string if value can't be converted.
+func canonType(in interface{}) string {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return ""
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return ""
+	}
+
+	return jsonType(out)
+}
+
 func dumpValue(value interface{}) string {
 	b, err := json.MarshalIndent(value, " ", "  ")
 	if err != nil {
@@ -99,3 +234,55 @@ func diffValues(expected, actual interface{}) string {
 
 	return "--- expected\n+++ actual\n" + str
 }
+
+// diffLines returns line-by-line diff between two strings, with unchanged
+// lines prefixed by " ", removed by "-", and added by "+".
+func diffLines(expected, actual string) string {
+	el := strings.Split(expected, "\n")
+	al := strings.Split(actual, "\n")
+
+	// avoid quadratic memory usage on huge inputs
+	if len(el)*len(al) > 10000000 {
+		return " (unavailable)"
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence
+	// of el[i:] and al[j:]
+	lcs := make([][]int, len(el)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(al)+1)
+	}
+	for i := len(el) - 1; i >= 0; i-- {
+		for j := len(al) - 1; j >= 0; j-- {
+			if el[i] == al[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("--- expected\n+++ actual\n")
+
+	i, j := 0, 0
+	for i < len(el) || j < len(al) {
+		switch {
+		case i < len(el) && j < len(al) && el[i] == al[j]:
+			buf.WriteString(" " + el[i] + "\n")
+			i++
+			j++
+		case j < len(al) && (i == len(el) || lcs[i][j+1] > lcs[i+1][j]):
+			buf.WriteString("+" + al[j] + "\n")
+			j++
+		default:
+			buf.WriteString("-" + el[i] + "\n")
+			i++
+		}
+	}
+
+	return buf.String()
+}
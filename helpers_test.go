@@ -1,8 +1,12 @@
 package httpexpect
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCanonNumber(t *testing.T) {
@@ -123,6 +127,99 @@ func TestCanonMap(t *testing.T) {
 	chain.reset()
 }
 
+func TestCanonCanonicalizer(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.canonicalizer = func(value interface{}) (interface{}, error) {
+		switch v := value.(type) {
+		case time.Time:
+			return v.UTC().Format(time.RFC3339), nil
+		case *big.Int:
+			f, _ := new(big.Float).SetInt(v).Float64()
+			return f, nil
+		case chan int:
+			return nil, errors.New("unexpected chan")
+		}
+		return value, nil
+	}
+
+	tm := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	v1, ok := canonValue(&chain, tm)
+	assert.True(t, ok)
+	assert.Equal(t, "2016-01-02T03:04:05Z", v1)
+	chain.assertOK(t)
+
+	v2, ok := canonMap(&chain, map[string]interface{}{
+		"time":  tm,
+		"array": []interface{}{big.NewInt(123), []byte("ab")},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"time":  "2016-01-02T03:04:05Z",
+		"array": []interface{}{123.0, "YWI="},
+	}, v2)
+	chain.assertOK(t)
+
+	v3, ok := canonArray(&chain, []time.Time{tm})
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"2016-01-02T03:04:05Z"}, v3)
+	chain.assertOK(t)
+
+	v4, ok := canonNumber(&chain, big.NewInt(123))
+	assert.True(t, ok)
+	assert.Equal(t, 123.0, v4)
+	chain.assertOK(t)
+
+	_, ok = canonValue(&chain, []interface{}{make(chan int)})
+	assert.False(t, ok)
+	chain.assertFailed(t)
+	chain.reset()
+
+	_, ok = canonNumber(&chain, make(chan int))
+	assert.False(t, ok)
+	chain.assertFailed(t)
+	chain.reset()
+}
+
+func TestDiffValues(t *testing.T) {
+	expected := map[string]interface{}{
+		"foo": "bar",
+		"baz": []interface{}{1.0, 2.0, 3.0},
+	}
+
+	actual := map[string]interface{}{
+		"foo": "bar",
+		"baz": []interface{}{1.0, 5.0, 3.0},
+	}
+
+	diff := diffValues(expected, actual)
+
+	assert.True(t, strings.HasPrefix(diff, "--- expected\n+++ actual\n"))
+	assert.Contains(t, diff, "-    1: 2,")
+	assert.Contains(t, diff, "+    1: 5,")
+	assert.Contains(t, diff, "   \"foo\": \"bar\"")
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := []struct {
+		expected string
+		actual   string
+		diff     string
+	}{
+		{"a\nb\nc", "a\nb\nc", " a\n b\n c\n"},
+		{"a\nb\nc", "a\nx\nc", " a\n-b\n+x\n c\n"},
+		{"a\nc", "a\nb\nc", " a\n+b\n c\n"},
+		{"a\nb\nc", "a\nc", " a\n-b\n c\n"},
+		{"", "a", "-\n+a\n"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, "--- expected\n+++ actual\n"+tc.diff,
+			diffLines(tc.expected, tc.actual))
+	}
+}
+
 func TestDiffErrors(t *testing.T) {
 	na := " (unavailable)"
 
@@ -1,8 +1,12 @@
 package httpexpect
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type mockClient struct {
@@ -21,6 +25,120 @@ func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
 	return nil, c.err
 }
 
+type mockBlockingClient struct{}
+
+func (c *mockBlockingClient) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+type mockNetError struct {
+	temporary bool
+}
+
+func (e mockNetError) Error() string {
+	return "network error"
+}
+
+func (e mockNetError) Timeout() bool {
+	return false
+}
+
+func (e mockNetError) Temporary() bool {
+	return e.temporary
+}
+
+type mockRetryClient struct {
+	failures int
+	err      error
+	status   int
+	attempts int
+	bodies   []string
+}
+
+func (c *mockRetryClient) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}
+	c.bodies = append(c.bodies, body)
+
+	c.attempts++
+
+	if c.attempts <= c.failures {
+		if c.err != nil {
+			return nil, c.err
+		}
+		return &http.Response{StatusCode: c.status}, nil
+	}
+
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+type mockPollClient struct {
+	ready    int
+	attempts int
+	bodies   []string
+}
+
+func (c *mockPollClient) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}
+	c.bodies = append(c.bodies, body)
+
+	c.attempts++
+
+	status := "pending"
+	if c.attempts >= c.ready {
+		status = "done"
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+		},
+		Body: ioutil.NopCloser(
+			bytes.NewBufferString(`{"status": "` + status + `"}`)),
+	}, nil
+}
+
+type mockBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *mockBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type mockNamedPrinter struct {
+	requests  []string
+	responses []string
+}
+
+func (p *mockNamedPrinter) Request(*http.Request) {
+	p.requests = append(p.requests, "")
+}
+
+func (p *mockNamedPrinter) Response(*http.Response, time.Duration) {
+	p.responses = append(p.responses, "")
+}
+
+func (p *mockNamedPrinter) NamedRequest(name string, req *http.Request) {
+	p.requests = append(p.requests, name)
+}
+
+func (p *mockNamedPrinter) NamedResponse(
+	name string, resp *http.Response, duration time.Duration) {
+	p.responses = append(p.responses, name)
+}
+
 type mockReporter struct {
 	testing  *testing.T
 	reported bool
@@ -34,3 +152,29 @@ func (r *mockReporter) Errorf(message string, args ...interface{}) {
 	r.testing.Logf("Fail: "+message, args...)
 	r.reported = true
 }
+
+type mockFatalReporter struct {
+	errors int
+	fatals int
+}
+
+func (r *mockFatalReporter) Errorf(message string, args ...interface{}) {
+	r.errors++
+}
+
+func (r *mockFatalReporter) Fatalf(message string, args ...interface{}) {
+	r.fatals++
+}
+
+type mockStructuredReporter struct {
+	errors   int
+	failures []Failure
+}
+
+func (r *mockStructuredReporter) Errorf(message string, args ...interface{}) {
+	r.errors++
+}
+
+func (r *mockStructuredReporter) Report(failure Failure) {
+	r.failures = append(r.failures, failure)
+}
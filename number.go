@@ -1,10 +1,15 @@
 package httpexpect
 
+import (
+	"math"
+)
+
 // Number provides methods to inspect attached float64 value
 // (Go representation of JSON number).
 type Number struct {
 	chain chain
 	value float64
+	delta float64
 }
 
 // NewNumber returns a new Number given a reporter used to report
@@ -15,7 +20,7 @@ type Number struct {
 // Example:
 //  number := NewNumber(t, 123.4)
 func NewNumber(reporter Reporter, value float64) *Number {
-	return &Number{makeChain(reporter), value}
+	return &Number{makeChain(reporter), value, 0}
 }
 
 // Raw returns underlying value attached to Number.
@@ -28,22 +33,114 @@ func (n *Number) Raw() float64 {
 	return n.value
 }
 
+// Require returns a copy of Number object that reports failures as fatal.
+// See Value.Require for details.
+//
+// Example:
+//  number := NewNumber(t, 123)
+//  number.Require().Equal(123)
+func (n *Number) Require() *Number {
+	ret := *n
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Alias returns a copy of Number object with assertion path replaced
+// by given name. See Value.Alias for details.
+//
+// Example:
+//  number := NewNumber(t, 123)
+//  number.Alias("count").Equal(123)
+func (n *Number) Alias(name string) *Number {
+	ret := *n
+	ret.chain.alias(name)
+	return &ret
+}
+
+// Round returns a new Number object with value rounded to the nearest
+// integer. Halfway values are rounded away from zero, like math.Round,
+// e.g. 2.5 becomes 3 and -2.5 becomes -3. Tolerance set by WithDelta
+// is preserved.
+//
+// Example:
+//  number := NewNumber(t, 99.5)
+//  number.Round().Equal(100)
+func (n *Number) Round() *Number {
+	return &Number{n.chain.enter(".Round"), round(n.value), n.delta}
+}
+
+// round is like math.Round, which is not available in all supported
+// Go versions
+func round(v float64) float64 {
+	t := math.Trunc(v)
+	if math.Abs(v-t) >= 0.5 {
+		t += math.Copysign(1, v)
+	}
+	return t
+}
+
+// Truncate returns a new Number object with fractional part of value
+// discarded, i.e. value rounded toward zero, like math.Trunc. Tolerance
+// set by WithDelta is preserved.
+//
+// Example:
+//  number := NewNumber(t, -99.9)
+//  number.Truncate().Equal(-99)
+func (n *Number) Truncate() *Number {
+	return &Number{n.chain.enter(".Truncate"), math.Trunc(n.value), n.delta}
+}
+
+// IsFinite succeedes if number is neither NaN nor positive or negative
+// infinity.
+//
+// Example:
+//  number := NewNumber(t, 123)
+//  number.IsFinite()
+func (n *Number) IsFinite() *Number {
+	if math.IsNaN(n.value) || math.IsInf(n.value, 0) {
+		n.chain.fail("\nexpected finite number, but got %v", n.value)
+	}
+	return n
+}
+
+// IsNaN succeedes if number is NaN.
+//
+// Note that NaN is never equal to any number, including NaN, so IsNaN
+// should be used instead of Equal to check for NaN.
+//
+// Example:
+//  number := NewNumber(t, math.NaN())
+//  number.IsNaN()
+func (n *Number) IsNaN() *Number {
+	if !math.IsNaN(n.value) {
+		n.chain.fail("\nexpected NaN, but got %v", n.value)
+	}
+	return n
+}
+
 // Equal succeedes if number is equal to given value.
 //
 // value should have numeric type convertible to float64. Before comparison,
-// it is converted to float64.
+// it is converted to float64. If value is NaN, failure is reported; use
+// IsNaN instead.
+//
+// If tolerance was set using WithDelta, Equal is equivalent to EqualDelta.
 //
 // Example:
 //  number := NewNumber(t, 123)
 //  number.Equal(float64(123))
 //  number.Equal(int32(123))
 func (n *Number) Equal(value interface{}) *Number {
-	v, ok := canonNumber(&n.chain, value)
+	if n.delta != 0 {
+		return n.EqualDelta(value, n.delta)
+	}
+	v, ok := n.canonValue(value)
 	if !ok {
 		return n
 	}
 	if !(n.value == v) {
-		n.chain.fail("expected number == %v, but got %v", v, n.value)
+		n.chain.failValues("Equal", v, n.value,
+			"expected number == %v, but got %v", v, n.value)
 	}
 	return n
 }
@@ -58,12 +155,81 @@ func (n *Number) Equal(value interface{}) *Number {
 //  number.NotEqual(float64(321))
 //  number.NotEqual(int32(321))
 func (n *Number) NotEqual(value interface{}) *Number {
-	v, ok := canonNumber(&n.chain, value)
+	if n.delta != 0 {
+		return n.NotEqualDelta(value, n.delta)
+	}
+	v, ok := n.canonValue(value)
 	if !ok {
 		return n
 	}
 	if !(n.value != v) {
-		n.chain.fail("expected number != %v, but got %v", v, n.value)
+		n.chain.failValues("NotEqual", v, n.value,
+			"expected number != %v, but got %v", v, n.value)
+	}
+	return n
+}
+
+// WithDelta returns a copy of Number object that uses given tolerance
+// in subsequent Equal and NotEqual calls, as if EqualDelta and
+// NotEqualDelta were called instead. Original object is not modified.
+//
+// EqualDelta and NotEqualDelta always use the delta passed to them,
+// regardless of WithDelta. Zero delta restores exact comparison.
+//
+// Example:
+//  number := NewNumber(t, 123.0001).WithDelta(0.01)
+//  number.Equal(123)     // success
+//  number.NotEqual(124)  // success
+func (n *Number) WithDelta(delta float64) *Number {
+	ret := *n
+	if delta < 0 || math.IsNaN(delta) {
+		ret.chain.fail("\nunexpected negative or NaN delta %v in WithDelta", delta)
+		return &ret
+	}
+	ret.delta = delta
+	return &ret
+}
+
+// EqualDelta succeedes if number is equal to given value within given
+// tolerance, i.e. if |number - value| <= delta.
+//
+// value should have numeric type convertible to float64. Before comparison,
+// it is converted to float64.
+//
+// Example:
+//  number := NewNumber(t, 123.0)
+//  number.EqualDelta(123.2, 0.3)
+func (n *Number) EqualDelta(value interface{}, delta float64) *Number {
+	v, ok := n.canonDelta(value, delta)
+	if !ok {
+		return n
+	}
+	if !(math.Abs(n.value-v) <= delta) {
+		n.chain.failValues("EqualDelta", v, n.value,
+			"expected number == %v (delta %v), but got %v",
+			v, delta, n.value)
+	}
+	return n
+}
+
+// NotEqualDelta succeedes if number is not equal to given value within
+// given tolerance, i.e. if |number - value| > delta.
+//
+// value should have numeric type convertible to float64. Before comparison,
+// it is converted to float64.
+//
+// Example:
+//  number := NewNumber(t, 123.0)
+//  number.NotEqualDelta(123.2, 0.1)
+func (n *Number) NotEqualDelta(value interface{}, delta float64) *Number {
+	v, ok := n.canonDelta(value, delta)
+	if !ok {
+		return n
+	}
+	if !(math.Abs(n.value-v) > delta) {
+		n.chain.failValues("NotEqualDelta", v, n.value,
+			"expected number != %v (delta %v), but got %v",
+			v, delta, n.value)
 	}
 	return n
 }
@@ -78,7 +244,7 @@ func (n *Number) NotEqual(value interface{}) *Number {
 //  number.Gt(float64(122))
 //  number.Gt(int32(122))
 func (n *Number) Gt(value interface{}) *Number {
-	v, ok := canonNumber(&n.chain, value)
+	v, ok := n.canonValue(value)
 	if !ok {
 		return n
 	}
@@ -98,7 +264,7 @@ func (n *Number) Gt(value interface{}) *Number {
 //  number.Ge(float64(122))
 //  number.Ge(int32(122))
 func (n *Number) Ge(value interface{}) *Number {
-	v, ok := canonNumber(&n.chain, value)
+	v, ok := n.canonValue(value)
 	if !ok {
 		return n
 	}
@@ -118,7 +284,7 @@ func (n *Number) Ge(value interface{}) *Number {
 //  number.Lt(float64(124))
 //  number.Lt(int32(124))
 func (n *Number) Lt(value interface{}) *Number {
-	v, ok := canonNumber(&n.chain, value)
+	v, ok := n.canonValue(value)
 	if !ok {
 		return n
 	}
@@ -138,7 +304,7 @@ func (n *Number) Lt(value interface{}) *Number {
 //  number.Le(float64(124))
 //  number.Le(int32(124))
 func (n *Number) Le(value interface{}) *Number {
-	v, ok := canonNumber(&n.chain, value)
+	v, ok := n.canonValue(value)
 	if !ok {
 		return n
 	}
@@ -159,11 +325,11 @@ func (n *Number) Le(value interface{}) *Number {
 //  number.InRange(100, 200)                  // success
 //  number.InRange(123, 123)                  // success
 func (n *Number) InRange(min, max interface{}) *Number {
-	a, ok := canonNumber(&n.chain, min)
+	a, ok := n.canonValue(min)
 	if !ok {
 		return n
 	}
-	b, ok := canonNumber(&n.chain, max)
+	b, ok := n.canonValue(max)
 	if !ok {
 		return n
 	}
@@ -172,3 +338,101 @@ func (n *Number) InRange(min, max interface{}) *Number {
 	}
 	return n
 }
+
+// Positive succeedes if number is greater than zero.
+//
+// Example:
+//  number := NewNumber(t, 123)
+//  number.Positive()
+func (n *Number) Positive() *Number {
+	if !(n.value > 0) {
+		n.chain.fail("expected positive number, but got %v", n.value)
+	}
+	return n
+}
+
+// Negative succeedes if number is less than zero.
+//
+// Example:
+//  number := NewNumber(t, -123)
+//  number.Negative()
+func (n *Number) Negative() *Number {
+	if !(n.value < 0) {
+		n.chain.fail("expected negative number, but got %v", n.value)
+	}
+	return n
+}
+
+// NonNegative succeedes if number is greater than or equal to zero.
+//
+// Example:
+//  number := NewNumber(t, 0)
+//  number.NonNegative()
+func (n *Number) NonNegative() *Number {
+	if !(n.value >= 0) {
+		n.chain.fail("expected non-negative number, but got %v", n.value)
+	}
+	return n
+}
+
+// NonPositive succeedes if number is less than or equal to zero.
+//
+// Example:
+//  number := NewNumber(t, 0)
+//  number.NonPositive()
+func (n *Number) NonPositive() *Number {
+	if !(n.value <= 0) {
+		n.chain.fail("expected non-positive number, but got %v", n.value)
+	}
+	return n
+}
+
+// IsMultipleOf succeedes if number is an integer multiple of given base.
+//
+// base should have numeric type convertible to float64 and should not be
+// zero. Small floating point errors are tolerated, so e.g. 0.3 is treated
+// as a multiple of 0.1.
+//
+// Example:
+//  number := NewNumber(t, 120)
+//  number.IsMultipleOf(10)  // success
+//  number.IsMultipleOf(7)   // failure
+func (n *Number) IsMultipleOf(base interface{}) *Number {
+	b, ok := n.canonValue(base)
+	if !ok {
+		return n
+	}
+	if b == 0 || math.IsInf(b, 0) {
+		n.chain.fail("\nunexpected base %v for multiple check, "+
+			"expected finite non-zero number", b)
+		return n
+	}
+	q := n.value / b
+	if math.IsNaN(q) || math.IsInf(q, 0) ||
+		math.Abs(q-math.Floor(q+0.5)) > 1e-9*math.Max(1, math.Abs(q)) {
+		n.chain.fail("expected number multiple of %v, but got %v", b, n.value)
+	}
+	return n
+}
+
+func (n *Number) canonValue(value interface{}) (float64, bool) {
+	v, ok := canonNumber(&n.chain, value)
+	if ok && math.IsNaN(v) {
+		n.chain.fail("\nunexpected NaN value for number comparison, " +
+			"use IsNaN() to check for NaN")
+		return 0, false
+	}
+	return v, ok
+}
+
+func (n *Number) canonDelta(value interface{}, delta float64) (float64, bool) {
+	v, ok := n.canonValue(value)
+	if !ok {
+		return 0, false
+	}
+	if delta < 0 || math.IsNaN(delta) {
+		n.chain.fail("\nunexpected negative or NaN delta %v", delta)
+		return 0, false
+	}
+	return v, true
+}
@@ -2,6 +2,7 @@ package httpexpect
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -10,7 +11,7 @@ func TestNumberFailed(t *testing.T) {
 
 	chain.fail("fail")
 
-	value := &Number{chain, 0}
+	value := &Number{chain, 0, 0}
 
 	value.chain.assertFailed(t)
 
@@ -21,6 +22,18 @@ func TestNumberFailed(t *testing.T) {
 	value.Lt(0)
 	value.Le(0)
 	value.InRange(0, 0)
+	value.IsFinite()
+	value.IsNaN()
+	value.Positive()
+	value.Negative()
+	value.NonNegative()
+	value.NonPositive()
+	value.IsMultipleOf(1)
+	value.EqualDelta(0, 0)
+	value.NotEqualDelta(0, 0)
+	value.WithDelta(1).Equal(0)
+	value.Round().chain.assertFailed(t)
+	value.Truncate().chain.assertFailed(t)
 }
 
 func TestNumberEqual(t *testing.T) {
@@ -236,3 +249,274 @@ func TestNumberConvertInRange(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestNumberIsFinite(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, v := range []float64{0, 1234.5, -1234.5, math.MaxFloat64} {
+		value := NewNumber(reporter, v)
+
+		value.IsFinite()
+		value.chain.assertOK(t)
+	}
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		value := NewNumber(reporter, v)
+
+		value.IsFinite()
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestNumberIsNaN(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewNumber(reporter, math.NaN())
+
+	value1.IsNaN()
+	value1.chain.assertOK(t)
+
+	for _, v := range []float64{0, 1234.5, math.Inf(1), math.Inf(-1)} {
+		value := NewNumber(reporter, v)
+
+		value.IsNaN()
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestNumberNaNComparison(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 1234)
+
+	value.Equal(math.NaN())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqual(math.NaN())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Gt(float32(math.NaN()))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.InRange(0, math.NaN())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Lt(math.Inf(1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Gt(math.Inf(-1))
+	value.chain.assertOK(t)
+	value.chain.reset()
+}
+
+func TestNumberSign(t *testing.T) {
+	cases := []struct {
+		value       float64
+		positive    bool
+		negative    bool
+		nonNegative bool
+		nonPositive bool
+	}{
+		{5, true, false, true, false},
+		{-5, false, true, false, true},
+		{0, false, false, true, true},
+		{math.NaN(), false, false, false, false},
+	}
+
+	check := func(value *Number, ok bool) {
+		if ok {
+			value.chain.assertOK(t)
+		} else {
+			value.chain.assertFailed(t)
+		}
+		value.chain.reset()
+	}
+
+	for _, tc := range cases {
+		reporter := newMockReporter(t)
+
+		value := NewNumber(reporter, tc.value)
+
+		check(value.Positive(), tc.positive)
+		check(value.Negative(), tc.negative)
+		check(value.NonNegative(), tc.nonNegative)
+		check(value.NonPositive(), tc.nonPositive)
+	}
+}
+
+func TestNumberIsMultipleOf(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 120)
+
+	value.IsMultipleOf(10)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsMultipleOf(int32(-40))
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsMultipleOf(120)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.IsMultipleOf(7)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.IsMultipleOf(0)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.IsMultipleOf(math.Inf(1))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.IsMultipleOf(math.NaN())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.IsMultipleOf("bad")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewNumber(reporter, 0).IsMultipleOf(10).chain.assertOK(t)
+	NewNumber(reporter, 0.3).IsMultipleOf(0.1).chain.assertOK(t)
+	NewNumber(reporter, 0.35).IsMultipleOf(0.1).chain.assertFailed(t)
+	NewNumber(reporter, math.Inf(1)).IsMultipleOf(10).chain.assertFailed(t)
+	NewNumber(reporter, math.NaN()).IsMultipleOf(10).chain.assertFailed(t)
+}
+
+func TestNumberEqualDelta(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 1234.5)
+
+	value.EqualDelta(1234.7, 0.3)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualDelta(1234.8, 0.2)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqualDelta(1234.7, 0.3)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotEqualDelta(1234.8, 0.2)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualDelta(1234.5, -1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualDelta(1234.5, math.NaN())
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualDelta("bad", 1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestNumberWithDelta(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 1234.5)
+
+	delta := value.WithDelta(0.3)
+	delta.chain.assertOK(t)
+
+	delta.Equal(1234.7)
+	delta.chain.assertOK(t)
+	delta.chain.reset()
+
+	delta.Equal(1235)
+	delta.chain.assertFailed(t)
+	delta.chain.reset()
+
+	delta.NotEqual(1234.7)
+	delta.chain.assertFailed(t)
+	delta.chain.reset()
+
+	delta.NotEqual(1235)
+	delta.chain.assertOK(t)
+	delta.chain.reset()
+
+	delta.EqualDelta(1235, 1)
+	delta.chain.assertOK(t)
+	delta.chain.reset()
+
+	value.Equal(1234.7)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	exact := delta.WithDelta(0)
+
+	exact.Equal(1234.7)
+	exact.chain.assertFailed(t)
+	exact.chain.reset()
+
+	value.WithDelta(-1).chain.assertFailed(t)
+	value.chain.assertOK(t)
+}
+
+func TestNumberRoundTruncate(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := []struct {
+		value    float64
+		round    float64
+		truncate float64
+	}{
+		{0, 0, 0},
+		{99.4, 99, 99},
+		{99.5, 100, 99},
+		{99.9, 100, 99},
+		{2.5, 3, 2},
+		{-2.5, -3, -2},
+		{-99.9, -100, -99},
+		{0.49999999999999994, 0, 0},
+		{-0.5, -1, -0},
+		{4503599627370497, 4503599627370497, 4503599627370497},
+		{100, 100, 100},
+	}
+
+	for _, tc := range cases {
+		value := NewNumber(reporter, tc.value)
+
+		assert.Equal(t, tc.round, value.Round().Raw())
+		assert.Equal(t, tc.truncate, value.Truncate().Raw())
+
+		value.Round().Equal(tc.round)
+		value.Truncate().Equal(tc.truncate)
+		value.chain.assertOK(t)
+	}
+
+	value := NewNumber(reporter, 99.6).WithDelta(1)
+
+	value.Truncate().Equal(100)
+	value.chain.assertOK(t)
+
+	value.Round().Equal(99.5)
+	value.chain.assertOK(t)
+
+	nan := NewNumber(reporter, math.NaN())
+
+	nan.Round().IsNaN()
+	nan.Truncate().IsNaN()
+	nan.chain.assertOK(t)
+
+	inf := NewNumber(reporter, math.Inf(-1))
+
+	inf.Round().Equal(math.Inf(-1))
+	inf.chain.assertOK(t)
+}
@@ -1,7 +1,10 @@
 package httpexpect
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 // Object provides methods to inspect attached map[string]interface{} object
@@ -20,7 +23,10 @@ type Object struct {
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 func NewObject(reporter Reporter, value map[string]interface{}) *Object {
-	chain := makeChain(reporter)
+	return makeObject(makeChain(reporter), value)
+}
+
+func makeObject(chain chain, value map[string]interface{}) *Object {
 	if value == nil {
 		chain.fail("expected non-nil map value")
 	} else {
@@ -39,6 +45,40 @@ func (o *Object) Raw() map[string]interface{} {
 	return o.value
 }
 
+// Require returns a copy of Object object that reports failures as fatal.
+// See Value.Require for details.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123})
+//  object.Require().ContainsKey("foo")
+func (o *Object) Require() *Object {
+	ret := *o
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Alias returns a copy of Object object with assertion path replaced
+// by given name. See Value.Alias for details.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123})
+//  object.Alias("settings").ContainsKey("foo")
+func (o *Object) Alias(name string) *Object {
+	ret := *o
+	ret.chain.alias(name)
+	return &ret
+}
+
+// Length returns a new Number object that may be used to inspect number
+// of keys in object.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
+//  object.Length().Equal(2)
+func (o *Object) Length() *Number {
+	return &Number{o.chain.enter(".Length"), float64(len(o.value)), 0}
+}
+
 // Keys returns a new Array object that may be used to inspect objects keys.
 //
 // Example:
@@ -49,7 +89,7 @@ func (o *Object) Keys() *Array {
 	for k := range o.value {
 		keys = append(keys, k)
 	}
-	return &Array{o.chain, keys}
+	return &Array{o.chain.enter(".Keys"), keys}
 }
 
 // Values returns a new Array object that may be used to inspect objects values.
@@ -62,7 +102,90 @@ func (o *Object) Values() *Array {
 	for _, v := range o.value {
 		values = append(values, v)
 	}
-	return &Array{o.chain, values}
+	return &Array{o.chain.enter(".Values"), values}
+}
+
+// Flatten returns a new Object with the same leaf values, but without
+// nesting. Keys of the new object are paths to leaf values in the original
+// object: keys of nested objects are joined with dots, and elements of
+// nested arrays are denoted by their index in brackets.
+//
+// Empty nested objects and arrays are kept as leaf values.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "user": map[string]interface{}{
+//          "address": map[string]interface{}{"zip": "12345"},
+//          "phones":  []interface{}{"111", "222"},
+//      },
+//  })
+//  flat := object.Flatten()
+//  flat.ValueEqual("user.address.zip", "12345")
+//  flat.ValueEqual("user.phones[1]", "222")
+func (o *Object) Flatten() *Object {
+	flat := map[string]interface{}{}
+	for k, v := range o.value {
+		flattenValue(flat, k, v)
+	}
+	return &Object{o.chain.enter(".Flatten"), flat}
+}
+
+func flattenValue(flat map[string]interface{}, key string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			flat[key] = v
+		}
+		for k, e := range v {
+			flattenValue(flat, key+"."+k, e)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			flat[key] = v
+		}
+		for i, e := range v {
+			flattenValue(flat, fmt.Sprintf("%s[%d]", key, i), e)
+		}
+	default:
+		flat[key] = v
+	}
+}
+
+// Pick returns a new Object containing only given keys of the original
+// object. Fails if any of the keys is missing.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"a": 1, "b": 2, "c": 3})
+//  object.Pick("a", "b").Equal(map[string]interface{}{"a": 1, "b": 2})
+func (o *Object) Pick(keys ...string) *Object {
+	picked := map[string]interface{}{}
+	for _, k := range keys {
+		v, ok := o.value[k]
+		if !ok {
+			o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
+				k, dumpValue(o.value))
+			return &Object{o.chain.enter(".Pick"), map[string]interface{}{}}
+		}
+		picked[k] = v
+	}
+	return &Object{o.chain.enter(".Pick"), picked}
+}
+
+// Omit returns a new Object containing all keys of the original object
+// except given ones. Keys missing in the original object are ignored.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"a": 1, "b": 2, "c": 3})
+//  object.Omit("a", "b").Equal(map[string]interface{}{"c": 3})
+func (o *Object) Omit(keys ...string) *Object {
+	omitted := map[string]interface{}{}
+	for k, v := range o.value {
+		omitted[k] = v
+	}
+	for _, k := range keys {
+		delete(omitted, k)
+	}
+	return &Object{o.chain.enter(".Omit"), omitted}
 }
 
 // Value returns a new Value object that may be used to inspect single value
@@ -76,9 +199,56 @@ func (o *Object) Value(key string) *Value {
 	if !ok {
 		o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
 			key, dumpValue(o.value))
-		return &Value{o.chain, nil}
+		return &Value{o.chain.enter("[%q]", key), nil}
+	}
+	return &Value{o.chain.enter("[%q]", key), value}
+}
+
+// ForEach invokes given function for every key and value of the object.
+// Every value is wrapped into a new Value object.
+//
+// Keys are iterated in sorted order, so failures are reported in the
+// same order on every run. If object is failed, fn is not invoked.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": "bar", "baz": "qux"})
+//  object.ForEach(func(key string, value *Value) {
+//      value.String().NotEmpty()
+//  })
+func (o *Object) ForEach(fn func(key string, value *Value)) *Object {
+	if o.chain.failed() {
+		return o
 	}
-	return &Value{o.chain, value}
+
+	keys := make([]string, 0, len(o.value))
+	for k := range o.value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fn(k, &Value{o.chain.enter("[%q]", k), o.value[k]})
+	}
+
+	return o
+}
+
+// Decode unmarshals underlying value into target, using JSON round-trip
+// conversion. target should be a non-nil pointer, e.g. to a struct or a map.
+//
+// Example:
+//  type User struct {
+//      Name string `json:"name"`
+//  }
+//
+//  var user User
+//  resp.JSON().Object().Decode(&user)
+func (o *Object) Decode(target interface{}) *Object {
+	if o.chain.failed() {
+		return o
+	}
+	decodeValue(&o.chain, o.value, target)
+	return o
 }
 
 // Empty succeedes if object is empty.
@@ -104,6 +274,9 @@ func (o *Object) NotEmpty() *Object {
 //
 // value should map[string]interface{} or struct.
 //
+// On failure, the message includes a diff between expected and actual
+// objects, so that changed keys may be found quickly in large objects.
+//
 // Example:
 //  object := NewObject(t, map[string]interface{}{"foo": 123})
 //  object.Equal(map[string]interface{}{"foo": 123})
@@ -113,7 +286,8 @@ func (o *Object) Equal(value interface{}) *Object {
 		return o
 	}
 	if !reflect.DeepEqual(expected, o.value) {
-		o.chain.fail("\nexpected object equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+		o.chain.failValues("Equal", expected, o.value,
+			"\nexpected object equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
 			dumpValue(expected),
 			dumpValue(o.value),
 			diffValues(expected, o.value))
@@ -121,6 +295,46 @@ func (o *Object) Equal(value interface{}) *Object {
 	return o
 }
 
+// EqualIgnoring succeedes if object is equal to expected object after
+// removing given keys from both of them. Keys missing in either object
+// are ignored. Before comparison, both objects are converted to canonical
+// form.
+//
+// It's useful to compare the whole object, except volatile fields like
+// timestamps or generated identifiers.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "id": 123, "name": "foo", "created": "2017-01-01T00:00:00Z",
+//  })
+//  object.EqualIgnoring([]string{"id", "created"}, map[string]interface{}{
+//      "name": "foo",
+//  })
+func (o *Object) EqualIgnoring(keys []string, expected map[string]interface{}) *Object {
+	exp, ok := canonMap(&o.chain, expected)
+	if !ok {
+		return o
+	}
+	actual := make(map[string]interface{}, len(o.value))
+	for k, v := range o.value {
+		actual[k] = v
+	}
+	for _, k := range keys {
+		delete(exp, k)
+		delete(actual, k)
+	}
+	if !reflect.DeepEqual(exp, actual) {
+		o.chain.failValues("EqualIgnoring", exp, actual,
+			"\nexpected object equal to:\n%s\n\nbut got:\n%s\n\n"+
+				"ignored keys:\n  %s\n\ndiff:\n%s",
+			dumpValue(exp),
+			dumpValue(actual),
+			strings.Join(keys, ", "),
+			diffValues(exp, actual))
+	}
+	return o
+}
+
 // NotEqual succeedes if object is not equal to another object.
 // Before comparison, both objects are converted to canonical form.
 //
@@ -135,7 +349,8 @@ func (o *Object) NotEqual(v interface{}) *Object {
 		return o
 	}
 	if reflect.DeepEqual(expected, o.value) {
-		o.chain.fail("\nexpected object NOT equal to:\n%s",
+		o.chain.failValues("NotEqual", expected, o.value,
+			"\nexpected object NOT equal to:\n%s",
 			dumpValue(expected))
 	}
 	return o
@@ -168,6 +383,49 @@ func (o *Object) NotContainsKey(key string) *Object {
 	return o
 }
 
+// ContainsKeys succeedes if object contains all given keys.
+// Other keys are allowed to be present too.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
+//  object.ContainsKeys("foo", "bar")
+func (o *Object) ContainsKeys(keys ...string) *Object {
+	missing := []string{}
+	for _, k := range keys {
+		if !o.containsKey(k) {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) != 0 {
+		o.chain.fail(
+			"\nexpected object containing keys:\n%s\n\nbut missing keys:\n%s"+
+				"\n\nin object:\n%s",
+			dumpValue(keys), dumpValue(missing), dumpValue(o.value))
+	}
+	return o
+}
+
+// NotContainsKeys succeedes if object contains none of given keys.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123, "bar": 456})
+//  object.NotContainsKeys("baz", "qux")
+func (o *Object) NotContainsKeys(keys ...string) *Object {
+	unexpected := []string{}
+	for _, k := range keys {
+		if o.containsKey(k) {
+			unexpected = append(unexpected, k)
+		}
+	}
+	if len(unexpected) != 0 {
+		o.chain.fail(
+			"\nexpected object NOT containing keys:\n%s\n\nbut found keys:\n%s"+
+				"\n\nin object:\n%s",
+			dumpValue(keys), dumpValue(unexpected), dumpValue(o.value))
+	}
+	return o
+}
+
 // ContainsMap succeedes if object contains given sub-object.
 // Before comparison, both objects are converted to canonical form.
 //
@@ -251,6 +509,65 @@ func (o *Object) ValueEqual(key string, value interface{}) *Object {
 	return o
 }
 
+// ValueEqualJSON is like ValueEqual, but expected value is given as JSON
+// literal. If literal is not valid JSON, failure is reported.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{
+//      "foo": map[string]interface{}{"bar": []interface{}{1, 2}},
+//  })
+//  object.ValueEqualJSON("foo", `{"bar": [1, 2]}`)
+func (o *Object) ValueEqualJSON(key, literal string) *Object {
+	if o.chain.failed() {
+		return o
+	}
+	value, ok := parseJSON(&o.chain, literal)
+	if !ok {
+		return o
+	}
+	return o.ValueEqual(key, value)
+}
+
+// ValueEqualStrict succeedes if object's value for given key is equal to
+// given value and has the same JSON type.
+//
+// Unlike ValueEqual, Config.Canonicalizer is not applied to given value,
+// so e.g. "1" is never equal to 1, even if canonicalizer converts numeric
+// strings to numbers. Only conversions defined by JSON rules are performed,
+// e.g. 1 and 1.0 are still equal. If JSON types differ, failure message
+// reports both types.
+//
+// Example:
+//  object := NewObject(t, map[string]interface{}{"foo": 123})
+//  object.ValueEqualStrict("foo", 123)    // success
+//  object.ValueEqualStrict("foo", "123")  // failure
+func (o *Object) ValueEqualStrict(key string, value interface{}) *Object {
+	if !o.containsKey(key) {
+		o.chain.fail("\nexpected object containing key '%s', but got:\n%s",
+			key, dumpValue(o.value))
+		return o
+	}
+	expected, ok := canonValueStrict(&o.chain, value)
+	if !ok {
+		return o
+	}
+	if jsonType(expected) != jsonType(o.value[key]) {
+		o.chain.fail(
+			"\nexpected value for key '%s' of type:\n  %s\n\nbut got value of type:\n  %s",
+			key, jsonType(expected), jsonType(o.value[key]))
+		return o
+	}
+	if !reflect.DeepEqual(expected, o.value[key]) {
+		o.chain.fail(
+			"\nexpected value for key '%s' equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+			key,
+			dumpValue(expected),
+			dumpValue(o.value[key]),
+			diffValues(expected, o.value[key]))
+	}
+	return o
+}
+
 // ValueNotEqual succeedes if object's value for given key is not equal to given value.
 // Before comparison, both values are converted to canonical form.
 //
@@ -2,6 +2,7 @@ package httpexpect
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strconv"
 	"testing"
 )
 
@@ -14,12 +15,17 @@ func TestObjectFailed(t *testing.T) {
 
 	value.chain.assertFailed(t)
 
+	assert.False(t, value.Length() == nil)
 	assert.False(t, value.Keys() == nil)
 	assert.False(t, value.Values() == nil)
 	assert.False(t, value.Value("foo") == nil)
 
+	value.Length().chain.assertFailed(t)
 	value.Keys().chain.assertFailed(t)
 	value.Values().chain.assertFailed(t)
+	value.Flatten().chain.assertFailed(t)
+	value.Pick("foo").chain.assertFailed(t)
+	value.Omit("foo").chain.assertFailed(t)
 	value.Value("foo").chain.assertFailed(t)
 
 	value.Empty()
@@ -28,10 +34,22 @@ func TestObjectFailed(t *testing.T) {
 	value.NotEqual(nil)
 	value.ContainsKey("foo")
 	value.NotContainsKey("foo")
+	value.ContainsKeys("foo")
+	value.NotContainsKeys("foo")
 	value.ContainsMap(nil)
 	value.NotContainsMap(nil)
 	value.ValueEqual("foo", nil)
+	value.ValueEqualStrict("foo", nil)
+	value.ValueEqualJSON("foo", "null")
 	value.ValueNotEqual("foo", nil)
+	value.EqualIgnoring([]string{"foo"}, nil)
+
+	value.ForEach(func(key string, value *Value) {
+		t.Errorf("unexpected ForEach call for failed object")
+	})
+
+	var target interface{}
+	value.Decode(&target)
 }
 
 func TestObjectGetters(t *testing.T) {
@@ -82,6 +100,42 @@ func TestObjectGetters(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectForEach(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": "a",
+		"bar": 123,
+		"baz": "",
+	})
+
+	var keys []string
+
+	value.ForEach(func(key string, v *Value) {
+		keys = append(keys, key)
+
+		v.chain.assertOK(t)
+
+		switch key {
+		case "foo":
+			v.String().Equal("a").chain.assertOK(t)
+		case "bar":
+			v.Number().Equal(123).chain.assertOK(t)
+		case "baz":
+			v.String().NotEmpty().chain.assertFailed(t)
+		}
+	})
+
+	assert.Equal(t, []string{"bar", "baz", "foo"}, keys)
+
+	value.chain.assertOK(t)
+
+	NewObject(reporter, map[string]interface{}{}).
+		ForEach(func(key string, v *Value) {
+			t.Errorf("unexpected ForEach call for empty object")
+		})
+}
+
 func TestObjectEmpty(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -112,6 +166,21 @@ func TestObjectEmpty(t *testing.T) {
 	value3.chain.reset()
 }
 
+func TestObjectLength(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewObject(reporter, map[string]interface{}{})
+
+	value1.Length().Equal(0).chain.assertOK(t)
+
+	value2 := NewObject(reporter, map[string]interface{}{"foo": 1, "bar": nil})
+
+	value2.Length().Equal(2).chain.assertOK(t)
+	value2.Length().Equal(1).chain.assertFailed(t)
+
+	value2.chain.assertOK(t)
+}
+
 func TestObjectEqualEmpty(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -184,6 +253,58 @@ func TestObjectEqual(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectEqualIgnoring(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"id":      123,
+		"name":    "foo",
+		"created": "2017-01-01T00:00:00Z",
+	})
+
+	value.EqualIgnoring([]string{"id", "created"}, map[string]interface{}{
+		"name": "foo",
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualIgnoring([]string{"id", "created", "missing"}, map[string]interface{}{
+		"id":   456,
+		"name": "foo",
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualIgnoring(nil, map[string]interface{}{
+		"id":      123,
+		"name":    "foo",
+		"created": "2017-01-01T00:00:00Z",
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.EqualIgnoring([]string{"id", "created"}, map[string]interface{}{
+		"name": "bar",
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualIgnoring([]string{"id"}, map[string]interface{}{
+		"name": "foo",
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualIgnoring([]string{"id", "created"}, map[string]interface{}{
+		"name":  "foo",
+		"extra": true,
+	})
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Equal(t, 3, len(value.Raw()))
+}
+
 func TestObjectEqualStruct(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -259,6 +380,40 @@ func TestObjectContainsKey(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectContainsKeys(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{"foo": 123, "bar": ""})
+
+	value.ContainsKeys()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsKeys("foo")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsKeys("foo", "bar")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ContainsKeys("foo", "BAR")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NotContainsKeys()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotContainsKeys("BAR", "baz")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NotContainsKeys("BAR", "bar")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
 func TestObjectContainsMapSuccess(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -526,6 +681,184 @@ func TestObjectValueEqualStruct(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectFlatten(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"id": 1,
+		"user": map[string]interface{}{
+			"address": map[string]interface{}{
+				"zip": "12345",
+			},
+			"phones": []interface{}{
+				"111",
+				map[string]interface{}{"number": "222"},
+			},
+			"tags":  []interface{}{},
+			"extra": map[string]interface{}{},
+		},
+	})
+
+	flat := value.Flatten()
+
+	flat.Equal(map[string]interface{}{
+		"id":                    1,
+		"user.address.zip":      "12345",
+		"user.phones[0]":        "111",
+		"user.phones[1].number": "222",
+		"user.tags":             []interface{}{},
+		"user.extra":            map[string]interface{}{},
+	})
+	flat.chain.assertOK(t)
+
+	flat.ValueEqual("user.address.zip", "12345")
+	flat.chain.assertOK(t)
+
+	value.chain.assertOK(t)
+}
+
+func TestObjectPickOmit(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"id":      1,
+		"name":    "foo",
+		"created": "2017-01-01",
+	})
+
+	value.Pick("id", "name").Equal(map[string]interface{}{
+		"id":   1,
+		"name": "foo",
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Pick().Empty()
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Omit("created", "missing").Equal(map[string]interface{}{
+		"id":   1,
+		"name": "foo",
+	})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.Omit().Length().Equal(3)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	picked := value.Pick("id", "missing")
+	picked.chain.assertFailed(t)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	assert.Equal(t, map[string]interface{}{
+		"id":      1.0,
+		"name":    "foo",
+		"created": "2017-01-01",
+	}, value.Raw())
+}
+
+func TestObjectValueEqualJSON(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{1, "a"},
+		},
+		"baz": nil,
+	})
+
+	value.ValueEqualJSON("foo", `{"bar": [1, "a"]}`)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualJSON("baz", `null`)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualJSON("foo", `{"bar": [1, "b"]}`)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqualJSON("foo", `{"bar": [1, "a"]`)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqualJSON("missing", `1`)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestObjectValueEqualStrict(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"num": 1,
+		"str": "1",
+		"arr": []interface{}{1, "a"},
+	})
+
+	value.ValueEqualStrict("num", 1)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("num", 1.0)
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("str", "1")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("arr", []interface{}{1.0, "a"})
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("num", "1")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("str", 1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("num", 2)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("missing", 1)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestObjectValueEqualStrictCanonicalizer(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{
+		"num": 1,
+	})
+
+	// converts numeric strings to numbers
+	value.chain.canonicalizer = func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, nil
+			}
+		}
+		return v, nil
+	}
+
+	value.ValueEqual("num", "1")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.ValueEqualStrict("num", "1")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
 func TestObjectConvertEqual(t *testing.T) {
 	type (
 		myMap map[string]interface{}
@@ -633,3 +966,33 @@ func TestObjectConvertValueEqual(t *testing.T) {
 	value.chain.assertFailed(t)
 	value.chain.reset()
 }
+
+func TestObjectDecode(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	type User struct {
+		Name  string   `json:"name"`
+		Roles []string `json:"roles"`
+	}
+
+	value := NewObject(reporter, map[string]interface{}{
+		"name":  "John",
+		"roles": []interface{}{"admin"},
+	})
+
+	var user User
+	value.Decode(&user)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, User{"John", []string{"admin"}}, user)
+
+	var m map[string]interface{}
+	value.Decode(&m)
+	value.chain.assertOK(t)
+
+	assert.Equal(t, "John", m["name"])
+
+	var array []interface{}
+	value.Decode(&array)
+	value.chain.assertFailed(t)
+}
@@ -1,10 +1,16 @@
 package httpexpect
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"github.com/moul/http2curl"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/http/httputil"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -29,6 +35,22 @@ func (p CompactPrinter) Request(req *http.Request) {
 func (CompactPrinter) Response(*http.Response, time.Duration) {
 }
 
+// NamedRequest implements NamedPrinter.NamedRequest.
+// If name is non-empty, it's printed before the request.
+func (p CompactPrinter) NamedRequest(name string, req *http.Request) {
+	if req != nil && name != "" {
+		p.logger.Logf("[%s] %s %s", name, req.Method, req.URL)
+	} else {
+		p.Request(req)
+	}
+}
+
+// NamedResponse implements NamedPrinter.NamedResponse.
+func (p CompactPrinter) NamedResponse(
+	name string, resp *http.Response, duration time.Duration) {
+	p.Response(resp, duration)
+}
+
 // DebugPrinter implements Printer. Uses net/http/httputil to dump
 // both requests and responses.
 type DebugPrinter struct {
@@ -72,15 +94,102 @@ func (p DebugPrinter) Response(resp *http.Response, duration time.Duration) {
 	p.logger.Logf("%s %s\n%s", lines[0], duration, lines[1])
 }
 
+// JSONPrinter implements Printer. It dumps requests and responses in the
+// same format as DebugPrinter, but re-indents bodies with "application/json"
+// Content-Type. Other bodies are printed unchanged.
+type JSONPrinter struct {
+	logger Logger
+}
+
+// NewJSONPrinter returns a new JSONPrinter given a logger.
+func NewJSONPrinter(logger Logger) JSONPrinter {
+	return JSONPrinter{logger}
+}
+
+// Request implements Printer.Request.
+func (p JSONPrinter) Request(req *http.Request) {
+	if req == nil {
+		return
+	}
+
+	dump, err := httputil.DumpRequestOut(req, false)
+	if err != nil {
+		panic(err)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			panic(err)
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	p.logger.Logf("%s%s", dump, indentJSON(req.Header, body))
+}
+
+// Response implements Printer.Response.
+func (p JSONPrinter) Response(resp *http.Response, duration time.Duration) {
+	if resp == nil {
+		return
+	}
+
+	dump, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		panic(err)
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			panic(err)
+		}
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	text := strings.Replace(string(dump), "\r\n", "\n", -1)
+	lines := strings.SplitN(text, "\n", 2)
+
+	p.logger.Logf("%s %s\n%s%s", lines[0], duration, lines[1],
+		indentJSON(resp.Header, body))
+}
+
+func indentJSON(header http.Header, body []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return body
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		return body
+	}
+
+	return buf.Bytes()
+}
+
 // CurlPrinter implements Printer. Uses http2curl to dump requests as
 // curl commands.
 type CurlPrinter struct {
-	logger Logger
+	logger    Logger
+	multiline bool
 }
 
 // NewCurlPrinter returns a new CurlPrinter given a logger.
 func NewCurlPrinter(logger Logger) CurlPrinter {
-	return CurlPrinter{logger}
+	return CurlPrinter{logger, false}
+}
+
+// NewCurlPrinterMultiline returns a new CurlPrinter given a logger.
+// Returned printer splits curl command into multiple lines, one option
+// per line, joined with backslash continuations, so that the command
+// may still be pasted into a shell.
+func NewCurlPrinterMultiline(logger Logger) CurlPrinter {
+	return CurlPrinter{logger, true}
 }
 
 // Request implements Printer.Request.
@@ -90,10 +199,165 @@ func (p CurlPrinter) Request(req *http.Request) {
 		if err != nil {
 			panic(err)
 		}
-		p.logger.Logf("%s", cmd.String())
+		if p.multiline {
+			p.logger.Logf("%s", formatCurlMultiline(cmd.String()))
+		} else {
+			p.logger.Logf("%s", cmd.String())
+		}
+	}
+}
+
+// formatCurlMultiline moves every option of curl command (except -X)
+// and the trailing URL to its own line.
+func formatCurlMultiline(cmd string) string {
+	args := splitShellWords(cmd)
+
+	var lines []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case i == 0:
+			lines = append(lines, args[i])
+		case args[i] == "-X" && i+1 < len(args):
+			lines[0] += " " + args[i] + " " + args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "-") && i+1 < len(args):
+			lines = append(lines, "  "+args[i]+" "+args[i+1])
+			i++
+		default:
+			lines = append(lines, "  "+args[i])
+		}
+	}
+
+	return strings.Join(lines, " \\\n")
+}
+
+// splitShellWords splits command into words separated by unquoted spaces.
+// Quotes and escapes are kept as is, so that every word remains a valid
+// shell word.
+func splitShellWords(cmd string) []string {
+	var (
+		words   []string
+		word    []byte
+		quoted  bool
+		escaped bool
+	)
+
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quoted:
+			quoted = c != '\''
+		case c == '\'':
+			quoted = true
+		case c == '\\':
+			escaped = true
+		case c == ' ':
+			if len(word) != 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		word = append(word, c)
+	}
+
+	if len(word) != 0 {
+		words = append(words, string(word))
 	}
+
+	return words
 }
 
 // Response implements Printer.Response.
 func (CurlPrinter) Response(*http.Response, time.Duration) {
 }
+
+// FailurePrinter implements Printer. It dumps requests and responses in
+// the same format as DebugPrinter, but only if an assertion fails.
+//
+// When FailurePrinter is added to Config.Printers, every request created
+// from that Config buffers dumps of the request and its response(s) and
+// prints them when failure is reported for the request, the response, or
+// any object obtained from the response. Dumps of requests without failures
+// are discarded.
+//
+// Request and Response methods do nothing when called directly.
+//
+// Example:
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.org/",
+//      Reporter: httpexpect.NewAssertReporter(t),
+//      Printers: []httpexpect.Printer{
+//          httpexpect.NewFailurePrinter(t, true),
+//      },
+//  })
+type FailurePrinter struct {
+	logger Logger
+	body   bool
+}
+
+// NewFailurePrinter returns a new FailurePrinter given a logger and body
+// flag. If body is true, request and response body is also printed.
+func NewFailurePrinter(logger Logger, body bool) FailurePrinter {
+	return FailurePrinter{logger, body}
+}
+
+// Request implements Printer.Request.
+func (FailurePrinter) Request(*http.Request) {
+}
+
+// Response implements Printer.Response.
+func (FailurePrinter) Response(*http.Response, time.Duration) {
+}
+
+// recordingPrinter is implemented by FailurePrinter, both by value and
+// by pointer; Request uses it to replace printer with a recorder
+type recordingPrinter interface {
+	newRecorder() *failureRecorder
+}
+
+func (p FailurePrinter) newRecorder() *failureRecorder {
+	r := &failureRecorder{logger: p.logger}
+	r.printer = NewDebugPrinter(r, p.body)
+	return r
+}
+
+type failureRecorder struct {
+	logger   Logger
+	printer  DebugPrinter
+	mutex    sync.Mutex
+	messages []string
+	flushed  bool
+}
+
+func (r *failureRecorder) Request(req *http.Request) {
+	r.printer.Request(req)
+}
+
+func (r *failureRecorder) Response(resp *http.Response, duration time.Duration) {
+	r.printer.Response(resp, duration)
+}
+
+func (r *failureRecorder) Logf(format string, args ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+func (r *failureRecorder) flush() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.flushed {
+		return
+	}
+	r.flushed = true
+
+	for _, m := range r.messages {
+		r.logger.Logf("%s", m)
+	}
+	r.messages = nil
+}
@@ -2,11 +2,21 @@ package httpexpect
 
 import (
 	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
 	"testing"
 )
 
+type mockLogger struct {
+	messages []string
+}
+
+func (l *mockLogger) Logf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
 func TestCompactPrinter(t *testing.T) {
 	printer := NewCompactPrinter(t)
 
@@ -42,3 +52,172 @@ func TestDebugPrinter(t *testing.T) {
 	printer.Response(&http.Response{}, 0)
 	printer.Response(nil, 0)
 }
+
+func TestJSONPrinter(t *testing.T) {
+	printer := NewJSONPrinter(t)
+
+	body1 := bytes.NewBufferString("body1")
+	body2 := bytes.NewBufferString("body2")
+
+	req1, _ := http.NewRequest("GET", "http://example.com", body1)
+	req2, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	printer.Request(req1)
+	printer.Request(req2)
+	printer.Request(nil)
+
+	printer.Response(&http.Response{Body: ioutil.NopCloser(body2)}, 0)
+	printer.Response(&http.Response{}, 0)
+	printer.Response(nil, 0)
+}
+
+func TestJSONPrinterIndent(t *testing.T) {
+	logger := &mockLogger{}
+
+	printer := NewJSONPrinter(logger)
+
+	req, _ := http.NewRequest("POST", "http://example.com",
+		bytes.NewBufferString(`{"a":[1,2]}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	printer.Request(req)
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type": []string{"text/plain"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`{"a":[1,2]}`)),
+	}
+
+	printer.Response(resp, 0)
+
+	assert.Equal(t, 2, len(logger.messages))
+	assert.Contains(t, logger.messages[0], "{\n  \"a\": [\n    1,\n    2\n  ]\n}")
+	assert.Contains(t, logger.messages[1], `{"a":[1,2]}`)
+
+	reqBody, _ := ioutil.ReadAll(req.Body)
+	assert.Equal(t, `{"a":[1,2]}`, string(reqBody))
+
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"a":[1,2]}`, string(respBody))
+}
+
+func TestCurlPrinter(t *testing.T) {
+	printer := NewCurlPrinter(t)
+
+	body := bytes.NewBufferString("body")
+
+	req1, _ := http.NewRequest("GET", "http://example.com", body)
+	req2, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	printer.Request(req1)
+	printer.Request(req2)
+	printer.Request(nil)
+
+	printer.Response(&http.Response{}, 0)
+	printer.Response(nil, 0)
+}
+
+func TestCurlPrinterMultiline(t *testing.T) {
+	logger := &mockLogger{}
+
+	printer := NewCurlPrinterMultiline(logger)
+
+	body := bytes.NewBufferString(`{"it's": "a b"}`)
+
+	req, _ := http.NewRequest("POST", "http://example.com/path?a=b", body)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Foo", "bar")
+
+	printer.Request(req)
+	printer.Request(nil)
+
+	expected := `curl -X 'POST' \
+  -d '{"it'\''s": "a b"}' \
+  -H 'Content-Type: application/json' \
+  -H 'X-Foo: bar' \
+  'http://example.com/path?a=b'`
+
+	assert.Equal(t, []string{expected}, logger.messages)
+}
+
+func TestFailurePrinter(t *testing.T) {
+	logger := &mockLogger{}
+
+	printer := NewFailurePrinter(logger, true)
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	printer.Request(req)
+	printer.Request(nil)
+
+	printer.Response(&http.Response{}, 0)
+	printer.Response(nil, 0)
+
+	assert.Equal(t, 0, len(logger.messages))
+}
+
+func TestFailurePrinterRequest(t *testing.T) {
+	logger := &mockLogger{}
+
+	config := Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{StatusCode: http.StatusOK},
+		},
+		Reporter: newMockReporter(t),
+		Printers: []Printer{
+			NewFailurePrinter(logger, true),
+		},
+	}
+
+	resp1 := NewRequest(config, "GET", "/ok").WithText("hello").Expect()
+	resp1.Status(http.StatusOK)
+	resp1.Body().Equal("hello")
+	resp1.chain.assertOK(t)
+
+	assert.Equal(t, 0, len(logger.messages))
+
+	resp2 := NewRequest(config, "GET", "/fail").WithText("hello").Expect()
+	resp2.Status(http.StatusOK)
+	resp2.Body().Equal("bye")
+	resp2.Body().Equal("bye")
+
+	assert.Equal(t, 2, len(logger.messages))
+	assert.Contains(t, logger.messages[0], "GET /fail")
+	assert.Contains(t, logger.messages[0], "text/plain")
+	assert.Contains(t, logger.messages[1], "200 OK")
+
+	resp2.Status(http.StatusNotFound)
+
+	assert.Equal(t, 2, len(logger.messages))
+}
+
+func TestFailurePrinterPointer(t *testing.T) {
+	logger := &mockLogger{}
+
+	printer := NewFailurePrinter(logger, true)
+
+	config := Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{StatusCode: http.StatusOK},
+		},
+		Reporter: newMockReporter(t),
+		Printers: []Printer{
+			&printer,
+		},
+	}
+
+	resp := NewRequest(config, "GET", "/fail").Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, 0, len(logger.messages))
+
+	resp.Status(http.StatusNotFound)
+
+	assert.Equal(t, 2, len(logger.messages))
+}
@@ -4,17 +4,19 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"sync"
 )
 
 // AssertReporter implements Reporter interface using `testify/assert'
 // package. Failures are non-fatal with this reporter.
 type AssertReporter struct {
 	backend *assert.Assertions
+	t       assert.TestingT
 }
 
 // NewAssertReporter returns a new AssertReporter object.
 func NewAssertReporter(t assert.TestingT) *AssertReporter {
-	return &AssertReporter{assert.New(t)}
+	return &AssertReporter{assert.New(t), t}
 }
 
 // Errorf implements Reporter.Errorf.
@@ -22,6 +24,15 @@ func (r *AssertReporter) Errorf(message string, args ...interface{}) {
 	r.backend.Fail(fmt.Sprintf(message, args...))
 }
 
+// Fatalf implements FatalReporter.Fatalf. If t passed to NewAssertReporter
+// has FailNow method, it's called after reporting failure.
+func (r *AssertReporter) Fatalf(message string, args ...interface{}) {
+	r.backend.Fail(fmt.Sprintf(message, args...))
+	if t, ok := r.t.(require.TestingT); ok {
+		t.FailNow()
+	}
+}
+
 // RequireReporter implements Reporter interface using `testify/require'
 // package. Failures fatal with this reporter.
 type RequireReporter struct {
@@ -37,3 +48,165 @@ func NewRequireReporter(t require.TestingT) *RequireReporter {
 func (r *RequireReporter) Errorf(message string, args ...interface{}) {
 	r.backend.FailNow(fmt.Sprintf(message, args...))
 }
+
+// Fatalf implements FatalReporter.Fatalf. It's equivalent to Errorf.
+func (r *RequireReporter) Fatalf(message string, args ...interface{}) {
+	r.backend.FailNow(fmt.Sprintf(message, args...))
+}
+
+// NoopReporter implements Reporter interface and discards all failures.
+// It may be used to measure cost of assertions in benchmarks, without
+// cost of reporting.
+//
+// Objects still become failed when an assertion fails, so subsequent
+// assertions on them are skipped.
+type NoopReporter struct{}
+
+// NewNoopReporter returns a new NoopReporter object.
+func NewNoopReporter() *NoopReporter {
+	return &NoopReporter{}
+}
+
+// Errorf implements Reporter.Errorf. It does nothing.
+func (r *NoopReporter) Errorf(message string, args ...interface{}) {
+}
+
+// Fatalf implements FatalReporter.Fatalf. It does nothing and doesn't
+// terminate test.
+func (r *NoopReporter) Fatalf(message string, args ...interface{}) {
+}
+
+// Failure contains information about a single failed assertion.
+type Failure struct {
+	// Message is the formatted failure message.
+	Message string
+
+	// Format and Args are the format string and arguments that were
+	// used to build Message.
+	Format string
+	Args   []interface{}
+
+	// TestName is the name set by Config.TestName or Expect.WithName.
+	// Empty if not set.
+	TestName string
+
+	// Context describes the request that the failed assertion belongs to,
+	// e.g. "GET http://example.com/path". Empty if failed assertion was
+	// not made on an object obtained from Request or Response.
+	Context string
+
+	// Fatal is true if failure was reported as fatal, e.g. by an object
+	// returned from Require method.
+	Fatal bool
+
+	// Path is the assertion path of the failed object relative to its root
+	// object, e.g. `JSON.Object["foo"].Array[3]`. Empty for root objects.
+	Path string
+
+	// Assertion is the name of the failed assertion method, e.g. "Equal".
+	// It's set only by assertions that compare a value with expected one:
+	// Equal and NotEqual of Value, Object, Array, String, Number, and
+	// Boolean; EqualDelta and NotEqualDelta of Number; EqualIgnoring of
+	// Object; and EqualUnordered and ContainsExactly of Array. For other
+	// failures, it's empty.
+	Assertion string
+
+	// Expected and Actual are the values compared by the failed assertion,
+	// after conversion to canonical form. Set only when Assertion is set.
+	Expected interface{}
+	Actual   interface{}
+}
+
+// FailureCollector implements Reporter interface and collects details about
+// every reported failure into a list of Failure structs. It may be used to
+// generate machine-readable reports.
+//
+// If backend reporter is given, every failure is also forwarded to it.
+// Failures are non-fatal with this reporter unless backend is fatal.
+//
+// FailureCollector is safe for concurrent use.
+//
+// Example:
+//  collector := httpexpect.NewFailureCollector(httpexpect.NewAssertReporter(t))
+//
+//  e := httpexpect.WithConfig(httpexpect.Config{
+//      BaseURL:  "http://example.org/",
+//      Reporter: collector,
+//  })
+//
+//  e.GET("/path").Expect().Status(http.StatusOK)
+//
+//  for _, f := range collector.Failures() {
+//      fmt.Println(f.Context, f.Message)
+//  }
+type FailureCollector struct {
+	backend  Reporter
+	mutex    sync.Mutex
+	failures []Failure
+}
+
+// NewFailureCollector returns a new FailureCollector object.
+//
+// backend may be nil. If nil, failures are only collected.
+func NewFailureCollector(backend Reporter) *FailureCollector {
+	return &FailureCollector{backend: backend}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *FailureCollector) Errorf(message string, args ...interface{}) {
+	r.Report(Failure{
+		Message: fmt.Sprintf(message, args...),
+		Format:  message,
+		Args:    args,
+	})
+}
+
+// Fatalf implements FatalReporter.Fatalf. Failure is collected and,
+// if backend is non-nil, reported to backend using its Fatalf method
+// (or Errorf, if backend doesn't implement FatalReporter).
+func (r *FailureCollector) Fatalf(message string, args ...interface{}) {
+	r.Report(Failure{
+		Message: fmt.Sprintf(message, args...),
+		Format:  message,
+		Args:    args,
+		Fatal:   true,
+	})
+}
+
+// Failures returns a copy of the list of failures collected so far,
+// in order in which they were reported.
+func (r *FailureCollector) Failures() []Failure {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	failures := make([]Failure, len(r.failures))
+	copy(failures, r.failures)
+
+	return failures
+}
+
+// Reset removes all collected failures.
+func (r *FailureCollector) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.failures = nil
+}
+
+// Report implements StructuredReporter.Report. Failure is collected and,
+// if backend is non-nil, forwarded to backend like in Errorf and Fatalf.
+func (r *FailureCollector) Report(failure Failure) {
+	r.mutex.Lock()
+	r.failures = append(r.failures, failure)
+	r.mutex.Unlock()
+
+	if r.backend == nil {
+		return
+	}
+
+	if fr, ok := r.backend.(FatalReporter); ok && failure.Fatal {
+		fr.Fatalf(failure.Format, failure.Args...)
+	} else {
+		r.backend.Errorf(failure.Format, failure.Args...)
+	}
+}
@@ -0,0 +1,207 @@
+package httpexpect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestFailureCollector(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	collector.Errorf("foo %d", 123)
+
+	value := NewString(collector, "bar")
+	value.Equal("bar")
+	value.Equal("baz")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 2, len(failures))
+
+	assert.Equal(t, "foo 123", failures[0].Message)
+	assert.Equal(t, "foo %d", failures[0].Format)
+	assert.Equal(t, []interface{}{123}, failures[0].Args)
+	assert.Equal(t, "", failures[0].Context)
+
+	assert.Contains(t, failures[1].Message, "baz")
+	assert.Equal(t, "", failures[1].Context)
+	assert.Equal(t, "Equal", failures[1].Assertion)
+	assert.Equal(t, "baz", failures[1].Expected)
+	assert.Equal(t, "bar", failures[1].Actual)
+
+	collector.Reset()
+
+	assert.Equal(t, 0, len(collector.Failures()))
+}
+
+func TestFailureCollectorBackend(t *testing.T) {
+	backend := newMockReporter(t)
+
+	collector := NewFailureCollector(backend)
+
+	NewNumber(collector, 1).Equal(2)
+
+	assert.True(t, backend.reported)
+	assert.Equal(t, 1, len(collector.Failures()))
+}
+
+func TestFailureCollectorContext(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	config := Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{StatusCode: http.StatusOK},
+		},
+		Reporter: collector,
+	}
+
+	resp := NewRequest(config, "GET", "/path").Expect()
+	resp.Header("foo").Equal("bar")
+	resp.Status(http.StatusNotFound)
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 2, len(failures))
+
+	assert.Equal(t, "GET http://example.com/path", failures[0].Context)
+	assert.Equal(t, `Header["foo"]`, failures[0].Path)
+
+	assert.Equal(t, "GET http://example.com/path", failures[1].Context)
+	assert.Equal(t, "", failures[1].Path)
+}
+
+type mockTestingT struct {
+	failed    bool
+	failedNow bool
+}
+
+func (t *mockTestingT) Errorf(message string, args ...interface{}) {
+	t.failed = true
+}
+
+func (t *mockTestingT) FailNow() {
+	t.failedNow = true
+}
+
+func TestAssertReporterFatal(t *testing.T) {
+	mt := &mockTestingT{}
+
+	reporter := NewAssertReporter(mt)
+
+	reporter.Errorf("foo")
+
+	assert.True(t, mt.failed)
+	assert.False(t, mt.failedNow)
+
+	reporter.Fatalf("foo")
+
+	assert.True(t, mt.failedNow)
+}
+
+func TestRequireReporterFatal(t *testing.T) {
+	mt := &mockTestingT{}
+
+	reporter := NewRequireReporter(mt)
+
+	reporter.Fatalf("foo")
+
+	assert.True(t, mt.failed)
+	assert.True(t, mt.failedNow)
+}
+
+func TestNoopReporter(t *testing.T) {
+	reporter := NewNoopReporter()
+
+	value := NewNumber(reporter, 1)
+	value.Equal(2)
+	value.chain.assertFailed(t)
+
+	value = NewNumber(reporter, 1).Require()
+	value.Equal(2)
+	value.chain.assertFailed(t)
+
+	value = NewNumber(reporter, 1)
+	value.Equal(1)
+	value.chain.assertOK(t)
+}
+
+func TestFailureCollectorFatal(t *testing.T) {
+	backend := &mockFatalReporter{}
+
+	collector := NewFailureCollector(backend)
+
+	NewNumber(collector, 1).Equal(2)
+	NewNumber(collector, 1).Require().Equal(2)
+	collector.Fatalf("foo")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 3, len(failures))
+	assert.False(t, failures[0].Fatal)
+	assert.True(t, failures[1].Fatal)
+	assert.True(t, failures[2].Fatal)
+
+	assert.Equal(t, 1, backend.errors)
+	assert.Equal(t, 2, backend.fatals)
+}
+
+func TestRequire(t *testing.T) {
+	reporter := &mockFatalReporter{}
+
+	config := Config{
+		Client: &mockClient{
+			resp: http.Response{StatusCode: http.StatusOK},
+		},
+		Reporter: reporter,
+	}
+
+	resp := NewRequest(config, "GET", "http://example.com").Expect()
+
+	resp.Status(http.StatusNotFound)
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+
+	assert.Equal(t, 1, reporter.errors)
+	assert.Equal(t, 0, reporter.fatals)
+
+	required := resp.Require()
+
+	required.Status(http.StatusNotFound)
+	required.chain.assertFailed(t)
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, 1, reporter.errors)
+	assert.Equal(t, 1, reporter.fatals)
+
+	resp.Require().Header("Foo").Equal("bar")
+
+	assert.Equal(t, 1, reporter.errors)
+	assert.Equal(t, 2, reporter.fatals)
+
+	NewValue(reporter, 1).Require().Null()
+	NewObject(reporter, map[string]interface{}{}).Require().NotEmpty()
+	NewArray(reporter, []interface{}{}).Require().NotEmpty()
+	NewString(reporter, "").Require().NotEmpty()
+	NewNumber(reporter, 1).Require().Equal(2)
+	NewBoolean(reporter, true).Require().False()
+
+	assert.Equal(t, 1, reporter.errors)
+	assert.Equal(t, 8, reporter.fatals)
+
+	NewNumber(newMockReporter(t), 1).Require().Equal(2)
+}
+
+func BenchmarkNoopReporter(b *testing.B) {
+	reporter := NewNoopReporter()
+
+	m := map[string]interface{}{
+		"foo": []interface{}{1, 2, 3},
+		"bar": map[string]interface{}{"baz": "qux"},
+	}
+
+	for i := 0; i < b.N; i++ {
+		NewObject(reporter, m).Equal(m).ValueEqual("foo", []interface{}{1, 2})
+	}
+}
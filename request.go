@@ -2,18 +2,25 @@ package httpexpect
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ajg/form"
 	"github.com/gavv/monotime"
 	"github.com/google/go-querystring/query"
+	"github.com/gorilla/websocket"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -31,6 +38,21 @@ type Request struct {
 	multipart  *multipart.Writer
 	typesetter string
 	bodysetter string
+	printers   []Printer
+	hooks      []func(*http.Request, *http.Response)
+	timeout    time.Duration
+	retries    int
+	retrypol   RetryPolicy
+	mindelay   time.Duration
+	maxdelay   time.Duration
+	redirpol   RedirectPolicy
+	maxredirs  int
+	proxy      *url.URL
+	clientcert *tls.Certificate
+	wsUpgrade  bool
+	streaming  bool
+	name       string
+	trace      *timingTrace
 }
 
 // NewRequest returns a new Request object.
@@ -41,10 +63,16 @@ type Request struct {
 // If Config.BaseURL is non-empty, it is prepended to final url,
 // separated by slash.
 //
+// If Config.Headers is non-empty, they are added to request, as if
+// WithHeaders was called.
+//
 // Example:
 //  req := NewRequest(config, "PUT", "http://example.org/path")
 func NewRequest(config Config, method, urlfmt string, args ...interface{}) *Request {
-	chain := makeChain(config.Reporter)
+	us := concatURLs(config.BaseURL, fmt.Sprintf(urlfmt, args...))
+
+	chain := makeConfigChain(config)
+	chain.context = method + " " + us
 
 	for _, a := range args {
 		if a == nil {
@@ -54,24 +82,50 @@ func NewRequest(config Config, method, urlfmt string, args ...interface{}) *Requ
 		}
 	}
 
-	us := concatURLs(config.BaseURL, fmt.Sprintf(urlfmt, args...))
+	httpReq := newHTTPRequest(&chain, config, method, us)
 
-	u, err := url.Parse(us)
-	if err != nil {
-		chain.fail(err.Error())
+	req := Request{
+		config:    config,
+		chain:     chain,
+		http:      httpReq,
+		mindelay:  time.Millisecond * 50,
+		maxdelay:  time.Second * 5,
+		maxredirs: -1,
 	}
 
-	req := Request{
-		config: config,
-		chain:  chain,
-		http: http.Request{
+	req.WithHeaders(config.Headers)
+
+	return &req
+}
+
+func newHTTPRequest(chain *chain, config Config, method, us string) http.Request {
+	if config.RequestFactory == nil {
+		u, err := url.Parse(us)
+		if err != nil {
+			chain.fail(err.Error())
+		}
+		return http.Request{
 			Method: method,
 			URL:    u,
 			Header: make(http.Header),
-		},
+		}
 	}
 
-	return &req
+	hr, err := config.RequestFactory(method, us)
+	if err != nil {
+		chain.fail(err.Error())
+		return http.Request{Method: method, Header: make(http.Header)}
+	}
+	if hr == nil || hr.URL == nil {
+		chain.fail("\nunexpected nil request or URL returned by Config.RequestFactory")
+		return http.Request{Method: method, Header: make(http.Header)}
+	}
+
+	httpReq := *hr
+	if httpReq.Header == nil {
+		httpReq.Header = make(http.Header)
+	}
+	return httpReq
 }
 
 func concatURLs(a, b string) string {
@@ -90,15 +144,363 @@ func concatURLs(a, b string) string {
 	return a + "/" + b
 }
 
+// WithContext sets the context of the request.
+//
+// The context is attached to http.Request and so is passed to Client.
+// http.Client aborts the request when the context is cancelled or its
+// deadline is exceeded; the error is reported as failure. If the context
+// is already done when Expect() is called, the request is not sent at all
+// and failure is reported.
+//
+// If WithTimeout is also used, the timeout context is derived from the given
+// context when Expect() is called, regardless of the order of calls. So
+// the request is aborted when either the given context is done or the
+// timeout expires.
+//
+// ctx should not be nil.
+//
+// Example:
+//  ctx, cancel := context.WithCancel(context.Background())
+//  defer cancel()
+//
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithContext(ctx)
+func (r *Request) WithContext(ctx context.Context) *Request {
+	if ctx == nil {
+		r.chain.fail("\nunexpected nil context in WithContext")
+		return r
+	}
+	r.http = *r.http.WithContext(ctx)
+	return r
+}
+
+// WithTimeout sets a deadline for the request.
+//
+// When Expect() is called, the request context (see WithContext) is wrapped
+// using context.WithTimeout(). The deadline covers both sending the request
+// and reading the response body. If it's exceeded, failure is reported, and
+// the failure message tells that the request timed out.
+//
+// Client should respect the request context; http.Client does.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithTimeout(time.Second)
+func (r *Request) WithTimeout(timeout time.Duration) *Request {
+	r.timeout = timeout
+	return r
+}
+
+// RetryPolicy defines which errors and responses cause WithRetry to resend
+// the request.
+type RetryPolicy int
+
+const (
+	// DontRetry disables retrying.
+	DontRetry RetryPolicy = iota
+
+	// RetryTemporaryNetworkErrors retries only temporary network errors
+	// and timeouts (net.Error with Temporary() or Timeout() returning true).
+	RetryTemporaryNetworkErrors
+
+	// RetryTemporaryNetworkAndServerErrors retries temporary network errors,
+	// timeouts, and responses with 5xx status codes.
+	RetryTemporaryNetworkAndServerErrors
+
+	// RetryAllErrors retries any error returned by Client, and responses
+	// with 4xx and 5xx status codes.
+	RetryAllErrors
+)
+
+// WithRetry enables resending the request up to maxRetries times (so there
+// are at most maxRetries+1 attempts) if an attempt fails according to policy.
+//
+// The request body is buffered before the first attempt, so that it can
+// be sent again. Delays between attempts grow exponentially, see
+// WithRetryDelay.
+//
+// Failure is reported only if the last attempt fails with an error. If the
+// last attempt receives a response, the response is returned as usual, even
+// if the policy would retry it.
+//
+// If WithTimeout or WithContext is used, all attempts share the same deadline.
+// Response time reported by Response.Time() is the time of the last attempt.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithRetry(3, RetryTemporaryNetworkAndServerErrors)
+func (r *Request) WithRetry(maxRetries int, policy RetryPolicy) *Request {
+	if maxRetries < 0 {
+		r.chain.fail("\nunexpected negative retries count in WithRetry: %d",
+			maxRetries)
+		return r
+	}
+	r.retries = maxRetries
+	r.retrypol = policy
+	return r
+}
+
+// WithRetryDelay sets minimum and maximum delays between attempts made by
+// WithRetry.
+//
+// The first retry is made after minDelay. Every next delay is twice longer
+// than the previous one, but not longer than maxDelay. Default delays are
+// 50ms and 5s.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithRetry(5, RetryAllErrors)
+//  req.WithRetryDelay(time.Millisecond * 10, time.Second)
+func (r *Request) WithRetryDelay(minDelay, maxDelay time.Duration) *Request {
+	if minDelay > maxDelay {
+		r.chain.fail(
+			"\ninvalid delays in WithRetryDelay: min %s is greater than max %s",
+			minDelay, maxDelay)
+		return r
+	}
+	r.mindelay = minDelay
+	r.maxdelay = maxDelay
+	return r
+}
+
+// RedirectPolicy defines how redirects are handled for the request.
+// See WithRedirectPolicy.
+type RedirectPolicy int
+
+const (
+	// DefaultRedirectPolicy leaves redirects handling to Client. For
+	// http.Client, it's defined by its CheckRedirect field.
+	DefaultRedirectPolicy RedirectPolicy = iota
+
+	// DontFollowRedirects disables following redirects. Response of the
+	// first request is returned, e.g. with "302 Found" status.
+	DontFollowRedirects
+
+	// FollowAllRedirects follows up to 10 subsequent redirects.
+	FollowAllRedirects
+
+	// FollowSameHostRedirects follows up to 10 subsequent redirects
+	// to the same host as the original request. Redirect to other host
+	// is not followed and its response is returned.
+	FollowSameHostRedirects
+)
+
+// WithRedirectPolicy sets policy for following redirects.
+//
+// For policies other than DefaultRedirectPolicy, Config.Client should be
+// *http.Client. The client is copied and its CheckRedirect is replaced in
+// the copy, so the original client is not affected.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithRedirectPolicy(DontFollowRedirects)
+//  req.Expect().Status(http.StatusFound)
+func (r *Request) WithRedirectPolicy(policy RedirectPolicy) *Request {
+	r.redirpol = policy
+	return r
+}
+
+// WithMaxRedirects sets maximum number of redirects to follow. If the
+// server responds with more redirects, the request fails with
+// "stopped after N redirects" error. Zero means that any redirect
+// causes failure.
+//
+// It may be combined with WithRedirectPolicy. The redirect policy is
+// checked first, so e.g. DontFollowRedirects still returns redirect
+// response instead of failing. Like WithRedirectPolicy, it requires
+// Config.Client to be *http.Client, which is copied.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithMaxRedirects(3)
+//  req.Expect().Status(http.StatusOK)
+func (r *Request) WithMaxRedirects(n int) *Request {
+	if r.chain.failed() {
+		return r
+	}
+	if n < 0 {
+		r.chain.fail("\nunexpected negative redirect count:\n  %d", n)
+		return r
+	}
+	r.maxredirs = n
+	return r
+}
+
+// WithProxy sets proxy URL to route request through, e.g.
+// "http://localhost:8080".
+//
+// Config.Client should be *http.Client with nil transport or
+// *http.Transport. The client and its transport are copied and Proxy
+// is replaced in the copy, so the original client is not affected.
+// Connections are not shared between requests using WithProxy.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/path")
+//  req.WithProxy("http://localhost:8080")
+func (r *Request) WithProxy(proxyURL string) *Request {
+	if r.chain.failed() {
+		return r
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		r.chain.fail(err.Error())
+		return r
+	}
+	if u.Scheme == "" || u.Host == "" {
+		r.chain.fail("\nexpected proxy URL with scheme and host, but got:\n  %q",
+			proxyURL)
+		return r
+	}
+	r.proxy = u
+	return r
+}
+
+// WithClientCert sets client certificate to be presented to server during
+// TLS handshake, for servers requiring mutual TLS.
+//
+// Like WithProxy, it requires Config.Client to be *http.Client with nil
+// transport or *http.Transport, which is copied with the certificate added
+// to its TLS config.
+//
+// Example:
+//  cert, _ := tls.LoadX509KeyPair("client.crt", "client.key")
+//  req := NewRequest(config, "GET", "https://example.org/path")
+//  req.WithClientCert(cert)
+func (r *Request) WithClientCert(cert tls.Certificate) *Request {
+	r.clientcert = &cert
+	return r
+}
+
+// WithStreaming disables reading response body when Expect returns.
+// Instead, body is read when it's first needed by Response methods.
+//
+// This allows Response.JSONStream to decode JSON array elements while
+// body is received, without buffering the whole body in memory. Other
+// Response methods, like Body or JSON, read and buffer body as usual.
+//
+// Note that connection is not released until body is read, so response
+// returned by Expect should be inspected by a method that reads body.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/events")
+//  req.WithStreaming()
+//  req.Expect().JSONStream().Count().Gt(100000)
+func (r *Request) WithStreaming() *Request {
+	r.streaming = true
+	return r
+}
+
+// WithWebsocketUpgrade enables upgrading the connection to WebSocket.
+//
+// When Expect() is called, WebSocket handshake is performed using
+// Config.WebsocketDialer instead of Config.Client. Request URL scheme is
+// replaced with "ws" or "wss", and request headers, query parameters and
+// cookies are sent within handshake request. Request method should be
+// "GET" and request body should not be set. WithRetry is not applied
+// to WebSocket handshake.
+//
+// If the server accepts the handshake, returned Response has "101 Switching
+// Protocols" status, and Response.Websocket() may be used to interact with
+// established connection. If the server rejects the handshake, returned
+// Response contains the server response, so that it can be inspected as
+// usual, and Response.Websocket() reports failure.
+//
+// Example:
+//  req := NewRequest(config, "GET", "/path")
+//  req.WithWebsocketUpgrade()
+//  ws := req.Expect().Status(http.StatusSwitchingProtocols).Websocket()
+//  defer ws.Close()
+func (r *Request) WithWebsocketUpgrade() *Request {
+	r.wsUpgrade = true
+	return r
+}
+
+// WithName sets logical name of the request, e.g. name of the route or
+// the test case.
+//
+// The name is passed to printers implementing NamedPrinter, which may use
+// it to tag printed requests and responses. Other printers ignore it.
+//
+// Example:
+//  req := NewRequest(config, "GET", "/users")
+//  req.WithName("list users")
+func (r *Request) WithName(name string) *Request {
+	r.name = name
+	return r
+}
+
+// WithPath substitutes named parameters in request URL path.
+//
+// value is converted to string using fmt.Sprint() and urlencoded.
+// Every occurrence of "{key}" in the path is replaced with the value.
+//
+// If path doesn't contain "{key}", failure is reported. If some parameters
+// are left unsubstituted when Expect() is called, failure is reported too.
+//
+// Example:
+//  req := NewRequest(config, "POST", "/repos/{user}/{repo}")
+//  req.WithPath("user", "gavv")
+//  req.WithPath("repo", "httpexpect")
+//  // path will be "/repos/gavv/httpexpect"
+func (r *Request) WithPath(key string, value interface{}) *Request {
+	if r.chain.failed() || r.http.URL == nil {
+		return r
+	}
+
+	if value == nil {
+		r.chain.fail("\nunexpected nil value for path parameter %q", key)
+		return r
+	}
+
+	rawValue := fmt.Sprint(value)
+
+	escapedPath := r.http.URL.EscapedPath()
+	escapedValue := escapePathSegment(rawValue)
+
+	found := false
+	for _, placeholder := range []string{"{" + key + "}", "%7B" + key + "%7D"} {
+		if strings.Contains(escapedPath, placeholder) {
+			escapedPath = strings.Replace(escapedPath, placeholder, escapedValue, -1)
+			found = true
+		}
+	}
+
+	if !found {
+		r.chain.fail("\nexpected path containing parameter %q, but got:\n  %s",
+			"{"+key+"}", r.http.URL.Path)
+		return r
+	}
+
+	r.http.URL.Path = strings.Replace(r.http.URL.Path, "{"+key+"}", rawValue, -1)
+	r.http.URL.RawPath = escapedPath
+
+	return r
+}
+
+// escapePathSegment is like url.PathEscape, which is not available in all
+// supported Go versions
+func escapePathSegment(s string) string {
+	escaped := (&url.URL{Path: s}).EscapedPath()
+	return strings.NewReplacer("/", "%2F", ";", "%3B", ",", "%2C").Replace(escaped)
+}
+
 // WithQuery adds query parameter to request URL.
 //
 // value is converted to string using fmt.Sprint() and urlencoded.
 //
+// If WithQuery is called multiple times with the same key, all values are
+// preserved and the parameter is repeated in the query string.
+//
 // Example:
 //  req := NewRequest(config, "PUT", "http://example.org/path")
 //  req.WithQuery("a", 123)
 //  req.WithQuery("b", "foo")
 //  // URL is now http://example.org/path?a=123&b=foo
+//
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithQuery("a", 123)
+//  req.WithQuery("a", 456)
+//  // URL is now http://example.org/path?a=123&a=456
 func (r *Request) WithQuery(key string, value interface{}) *Request {
 	if r.query == nil {
 		r.query = r.http.URL.Query()
@@ -158,8 +560,24 @@ func (r *Request) WithQueryObject(object interface{}) *Request {
 	return r
 }
 
+// WithHost sets request host to given string.
+//
+// The host is sent in "Host" header instead of URL host, so the request
+// is still sent to the address from URL. This is equivalent to
+// WithHeader("Host", host).
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://127.0.0.1/path")
+//  req.WithHost("example.org")
+func (r *Request) WithHost(host string) *Request {
+	r.http.Host = host
+	return r
+}
+
 // WithHeaders adds given headers to request.
 //
+// Like WithHeader, it replaces previously set values of the same headers.
+//
 // Example:
 //  req := NewRequest(config, "PUT", "http://example.org/path")
 //  req.WithHeaders(map[string]string{
@@ -174,6 +592,9 @@ func (r *Request) WithHeaders(headers map[string]string) *Request {
 
 // WithHeader adds given single header to request.
 //
+// If header with the same name was already set, its value is replaced,
+// not appended.
+//
 // Example:
 //  req := NewRequest(config, "PUT", "http://example.org/path")
 //  req.WithHeader("Content-Type": "application/json")
@@ -185,10 +606,87 @@ func (r *Request) WithHeader(k, v string) *Request {
 		if r.typesetter == "" {
 			r.typesetter = "WithHeader"
 		}
-		r.http.Header.Add(k, v)
+		r.http.Header.Set(k, v)
 	default:
-		r.http.Header.Add(k, v)
+		r.http.Header.Set(k, v)
+	}
+	return r
+}
+
+// WithCookies adds given cookies to request.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithCookies(map[string]string{
+//      "foo": "aa",
+//      "bar": "bb",
+//  })
+func (r *Request) WithCookies(cookies map[string]string) *Request {
+	for k, v := range cookies {
+		r.WithCookie(k, v)
+	}
+	return r
+}
+
+// WithCookie adds given single cookie to request.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithCookie("name", "value")
+func (r *Request) WithCookie(k, v string) *Request {
+	r.http.AddCookie(&http.Cookie{
+		Name:  k,
+		Value: v,
+	})
+	return r
+}
+
+// WithBasicAuth sets the request's Authorization header to use HTTP
+// Basic Authentication with the provided username and password.
+//
+// With HTTP Basic Authentication the provided username and password
+// are not encrypted.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithBasicAuth("john", "secret")
+func (r *Request) WithBasicAuth(username, password string) *Request {
+	r.http.SetBasicAuth(username, password)
+	return r
+}
+
+// WithBasicAuthFromEnv is like WithBasicAuth, but reads username and
+// password from environment variables with given names. If any of the
+// variables is not set, failure is reported.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithBasicAuthFromEnv("API_USER", "API_PASSWORD")
+func (r *Request) WithBasicAuthFromEnv(userVar, passVar string) *Request {
+	if r.chain.failed() {
+		return r
+	}
+	username, ok := os.LookupEnv(userVar)
+	if !ok {
+		r.chain.fail("\nexpected environment variable %q to be set", userVar)
+		return r
+	}
+	password, ok := os.LookupEnv(passVar)
+	if !ok {
+		r.chain.fail("\nexpected environment variable %q to be set", passVar)
+		return r
 	}
+	return r.WithBasicAuth(username, password)
+}
+
+// WithBearer sets the request's Authorization header to use bearer
+// token authentication (RFC 6750) with the provided token.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithBearer("mF_9.B5f-4.1JqM")
+func (r *Request) WithBearer(token string) *Request {
+	r.http.Header.Set("Authorization", "Bearer "+token)
 	return r
 }
 
@@ -205,6 +703,29 @@ func (r *Request) WithBody(reader io.Reader) *Request {
 	return r
 }
 
+// WithChunked sets given reader for request body and enables chunked
+// Transfer-Encoding, so that the body is streamed to the server without
+// known length.
+//
+// Unlike WithBody, it forces chunked encoding even if the reader is small
+// or empty.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithHeader("Content-Type": "application/json")
+//  req.WithChunked(bytes.NewBufferString(`{"foo": 123}`))
+func (r *Request) WithChunked(reader io.Reader) *Request {
+	if reader == nil {
+		r.chain.fail("\nunexpected nil reader in WithChunked")
+		return r
+	}
+	r.setBody("WithChunked", reader, -1)
+	if !r.chain.failed() {
+		r.http.TransferEncoding = []string{"chunked"}
+	}
+	return r
+}
+
 // WithBytes is like WithBody, but gets body as a slice of bytes.
 //
 // Example:
@@ -220,6 +741,23 @@ func (r *Request) WithBytes(b []byte) *Request {
 	return r
 }
 
+// WithBytesFromFile is like WithBytes, but reads body from given file.
+// Failure is reported if the file can't be read.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithHeader("Content-Type": "application/octet-stream")
+//  req.WithBytesFromFile("testdata/payload.bin")
+func (r *Request) WithBytesFromFile(path string) *Request {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		r.chain.fail(err.Error())
+		return r
+	}
+	r.setBody("WithBytesFromFile", bytes.NewReader(b), len(b))
+	return r
+}
+
 // WithText sets Content-Type header to "text/plain; charset=utf-8" and
 // sets body to given string.
 //
@@ -235,6 +773,9 @@ func (r *Request) WithText(s string) *Request {
 // WithJSON sets Content-Type header to "application/json; charset=utf-8"
 // and sets body to object, marshaled using json.Marshal().
 //
+// If contentType is given, it's used instead of the default Content-Type,
+// e.g. "application/vnd.api+json".
+//
 // Example:
 //  type MyJSON struct {
 //      Foo int `json:"foo"`
@@ -245,19 +786,59 @@ func (r *Request) WithText(s string) *Request {
 //
 //  req := NewRequest(config, "PUT", "http://example.org/path")
 //  req.WithJSON(map[string]interface{}{"foo": 123})
-func (r *Request) WithJSON(object interface{}) *Request {
+//
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithJSON(map[string]interface{}{"foo": 123}, "application/vnd.api+json")
+func (r *Request) WithJSON(object interface{}, contentType ...string) *Request {
+	if len(contentType) > 1 {
+		r.chain.fail("\nunexpected multiple content types in WithJSON")
+		return r
+	}
+
 	b, err := json.Marshal(object)
 	if err != nil {
 		r.chain.fail(err.Error())
 		return r
 	}
 
-	r.setType("WithJSON", "application/json; charset=utf-8")
+	typ := "application/json; charset=utf-8"
+	if len(contentType) != 0 {
+		typ = contentType[0]
+	}
+
+	r.setType("WithJSON", typ)
 	r.setBody("WithJSON", bytes.NewReader(b), len(b))
 
 	return r
 }
 
+// WithJSONFromFile sets Content-Type header to "application/json;
+// charset=utf-8" and sets body to contents of given file, which is sent
+// as is. Failure is reported if the file can't be read or doesn't contain
+// valid JSON.
+//
+// Example:
+//  req := NewRequest(config, "PUT", "http://example.org/path")
+//  req.WithJSONFromFile("testdata/user.json")
+func (r *Request) WithJSONFromFile(path string) *Request {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		r.chain.fail(err.Error())
+		return r
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		r.chain.fail("\ninvalid JSON in file %s:\n  %s", path, err.Error())
+		return r
+	}
+
+	r.setType("WithJSONFromFile", "application/json; charset=utf-8")
+	r.setBody("WithJSONFromFile", bytes.NewReader(b), len(b))
+
+	return r
+}
+
 // WithForm sets Content-Type header to "application/x-www-form-urlencoded"
 // or (if WithMultipart() was called) "multipart/form-data", converts given
 // object to url.Values using github.com/ajg/form and adds it to request body.
@@ -266,7 +847,7 @@ func (r *Request) WithJSON(object interface{}) *Request {
 // contain "form" struct tag, similar to "json" struct tag for json.Marshal().
 // See https://github.com/ajg/form for details.
 //
-// Multiple WithForm(), WithField(), and WithFile() calls may be combined.
+// Multiple WithForm(), WithFormField(), and WithFile() calls may be combined.
 // If WithMultipart() is called, it should be called first.
 //
 // Example:
@@ -314,19 +895,19 @@ func (r *Request) WithForm(object interface{}) *Request {
 	return r
 }
 
-// WithField sets Content-Type header to "application/x-www-form-urlencoded"
+// WithFormField sets Content-Type header to "application/x-www-form-urlencoded"
 // or (if WithMultipart() was called) "multipart/form-data", converts given
 // value to string using fmt.Sprint() and adds it to request body.
 //
-// Multiple WithForm(), WithField(), and WithFile() calls may be combined.
+// Multiple WithForm(), WithFormField(), and WithFile() calls may be combined.
 // If WithMultipart() is called, it should be called first.
 //
 // Example:
 //  req := NewRequest(config, "PUT", "http://example.org/path")
-//  req.WithField("foo", 123)
-func (r *Request) WithField(key string, value interface{}) *Request {
+//  req.WithFormField("foo", 123)
+func (r *Request) WithFormField(key string, value interface{}) *Request {
 	if r.multipart != nil {
-		r.setType("WithField", "multipart/form-data")
+		r.setType("WithFormField", "multipart/form-data")
 
 		err := r.multipart.WriteField(key, fmt.Sprint(value))
 		if err != nil {
@@ -334,7 +915,7 @@ func (r *Request) WithField(key string, value interface{}) *Request {
 			return r
 		}
 	} else {
-		r.setType("WithField", "application/x-www-form-urlencoded")
+		r.setType("WithFormField", "application/x-www-form-urlencoded")
 
 		if r.form == nil {
 			r.form = make(url.Values)
@@ -344,13 +925,21 @@ func (r *Request) WithField(key string, value interface{}) *Request {
 	return r
 }
 
+// WithField is an alias for WithFormField.
+//
+// Deprecated: use WithFormField instead.
+func (r *Request) WithField(key string, value interface{}) *Request {
+	return r.WithFormField(key, value)
+}
+
 // WithFile sets Content-Type header to "multipart/form-data", reads given
 // file and adds its contents to request body.
 //
 // If reader is given, it's used to read file contents. Otherwise, os.Open()
-// is used to read a file with given path.
+// is used to read a file with given path. If the file can't be read, failure
+// is reported.
 //
-// Multiple WithForm(), WithField(), and WithFile() calls may be combined.
+// Multiple WithForm(), WithFormField(), and WithFile() calls may be combined.
 // WithMultipart() should be called before WithFile(), otherwise WithFile()
 // fails.
 //
@@ -371,12 +960,6 @@ func (r *Request) WithFile(key, path string, reader ...io.Reader) *Request {
 		return r
 	}
 
-	wr, err := r.multipart.CreateFormFile(key, path)
-	if err != nil {
-		r.chain.fail(err.Error())
-		return r
-	}
-
 	var rd io.Reader
 	if len(reader) != 0 && reader[0] != nil {
 		rd = reader[0]
@@ -390,6 +973,12 @@ func (r *Request) WithFile(key, path string, reader ...io.Reader) *Request {
 		defer f.Close()
 	}
 
+	wr, err := r.multipart.CreateFormFile(key, path)
+	if err != nil {
+		r.chain.fail(err.Error())
+		return r
+	}
+
 	if _, err := io.Copy(wr, rd); err != nil {
 		r.chain.fail(err.Error())
 		return r
@@ -414,11 +1003,11 @@ func (r *Request) WithFileBytes(key, path string, data []byte) *Request {
 
 // WithMultipart sets Content-Type header to "multipart/form-data".
 //
-// After this call, WithForm() and WithField() switch to multipart form
+// After this call, WithForm() and WithFormField() switch to multipart form
 // instead of urlencoded form.
 //
 // If WithMultipart() is called, it should be called before WithForm()
-// or WithField().
+// or WithFormField().
 //
 // WithFile() always requires WithMultipart() to be called first.
 //
@@ -441,7 +1030,8 @@ func (r *Request) WithMultipart() *Request {
 // Expect constructs http.Request, sends it, receives http.Response, and
 // returns a new Response object to inspect received response.
 //
-// Request is sent using Config.Client interface.
+// Request is sent using Config.Client interface, or Config.WebsocketDialer
+// interface in case of WebSocket request (see WithWebsocketUpgrade).
 //
 // Example:
 //  req := NewRequest(config, "PUT", "http://example.org/path")
@@ -449,11 +1039,197 @@ func (r *Request) WithMultipart() *Request {
 //  resp := req.Expect()
 //  resp.Status(http.StatusOK)
 func (r *Request) Expect() *Response {
+	if r.timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.http.Context(), r.timeout)
+		defer cancel()
+
+		r.http = *r.http.WithContext(ctx)
+	}
+
+	r.setupPrinters()
+
 	r.encodeRequest()
 
+	if r.wsUpgrade {
+		resp, conn, elapsed := r.sendWebsocketRequest()
+
+		response := makeResponse(
+			r.chain, resp, elapsed, false, r.config.MaxBodySize, false)
+		response.websocket = conn
+
+		return response
+	}
+
 	resp, elapsed := r.sendRequest()
 
-	return makeResponse(r.chain, resp, elapsed)
+	response := makeResponse(r.chain, resp, elapsed,
+		!r.config.DisableDecompression, r.config.MaxBodySize, r.streaming)
+	response.timing = r.trace
+
+	return response
+}
+
+// ExpectUntil is like Expect, but sends the request repeatedly, with given
+// interval, until cond returns true for received response or timeout
+// elapses. Request body is buffered and re-sent with every attempt.
+//
+// cond may use Response methods to inspect the response; failures reported
+// inside cond are not reported to Reporter, but just make the attempt
+// unsuccessful. ExpectUntil returns the last received response. If timeout
+// elapses before cond returns true, failure is reported.
+//
+// WebSocket requests are not supported.
+//
+// Example:
+//  req := NewRequest(config, "GET", "http://example.org/jobs/123")
+//  req.ExpectUntil(time.Second*10, time.Millisecond*100, func(r *Response) bool {
+//      return r.JSON().Object().Value("status").Raw() == "done"
+//  })
+func (r *Request) ExpectUntil(timeout, interval time.Duration,
+	cond func(*Response) bool) *Response {
+	if r.wsUpgrade {
+		r.chain.fail("\nunexpected WebSocket request in ExpectUntil")
+	}
+
+	if cond == nil {
+		r.chain.fail("\nunexpected nil condition in ExpectUntil")
+	}
+
+	r.setupPrinters()
+
+	r.encodeRequest()
+
+	body := r.bufferBody()
+
+	// jar cookies are added on every attempt, so keep only cookies
+	// set explicitly to restore them before each attempt
+	cookies := append([]string(nil), r.http.Header["Cookie"]...)
+
+	deadline := time.Now().Add(timeout)
+
+	for attempt := 1; ; attempt++ {
+		if r.chain.failed() {
+			return makeResponse(r.chain, nil, 0, false, 0, false)
+		}
+
+		resp := r.expectAttempt(body, cookies)
+
+		if r.chain.failed() {
+			return resp
+		}
+
+		probe := *resp
+		probe.chain.reporter = NewFailureCollector(nil)
+		probe.chain.failhooks = nil
+
+		if cond(&probe) {
+			return resp
+		}
+
+		if !time.Now().Add(interval).Before(deadline) {
+			r.chain.fail("\nexpected response satisfying condition within %s,"+
+				" but got none after %d attempt(s)", timeout, attempt)
+			resp.chain = r.chain
+			return resp
+		}
+
+		if !r.sleep(interval) {
+			r.failSend(r.http.Context().Err())
+			resp.chain = r.chain
+			return resp
+		}
+	}
+}
+
+func (r *Request) bufferBody() []byte {
+	if r.chain.failed() || r.http.Body == nil {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(r.http.Body)
+	if err != nil {
+		r.chain.fail(err.Error())
+		return nil
+	}
+
+	return b
+}
+
+func (r *Request) expectAttempt(body []byte, cookies []string) *Response {
+	if body != nil {
+		r.http.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if len(cookies) != 0 {
+		r.http.Header["Cookie"] = append([]string(nil), cookies...)
+	} else {
+		r.http.Header.Del("Cookie")
+	}
+
+	if r.timeout > 0 {
+		ctx := r.http.Context()
+
+		attemptCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+
+		r.http = *r.http.WithContext(attemptCtx)
+		defer func() {
+			r.http = *r.http.WithContext(ctx)
+		}()
+	}
+
+	resp, elapsed := r.sendRequest()
+
+	response := makeResponse(r.chain, resp, elapsed,
+		!r.config.DisableDecompression, r.config.MaxBodySize, r.streaming)
+	response.timing = r.trace
+
+	return response
+}
+
+func (r *Request) setupPrinters() {
+	r.printers = make([]Printer, 0, len(r.config.Printers))
+
+	for _, printer := range r.config.Printers {
+		if rp, ok := printer.(recordingPrinter); ok {
+			recorder := rp.newRecorder()
+			r.chain.failhooks = append(r.chain.failhooks, recorder.flush)
+			printer = recorder
+		}
+		r.printers = append(r.printers, printer)
+	}
+}
+
+func (r *Request) interceptRequest() {
+	for _, hook := range r.hooks {
+		hook(&r.http, nil)
+	}
+}
+
+func (r *Request) interceptResponse(resp *http.Response) {
+	for _, hook := range r.hooks {
+		hook(&r.http, resp)
+	}
+}
+
+func (r *Request) printRequest() {
+	for _, printer := range r.printers {
+		if np, ok := printer.(NamedPrinter); ok {
+			np.NamedRequest(r.name, &r.http)
+		} else {
+			printer.Request(&r.http)
+		}
+	}
+}
+
+func (r *Request) printResponse(resp *http.Response, duration time.Duration) {
+	for _, printer := range r.printers {
+		if np, ok := printer.(NamedPrinter); ok {
+			np.NamedResponse(r.name, resp, duration)
+		} else {
+			printer.Response(resp, duration)
+		}
+	}
 }
 
 func (r *Request) setType(newSetter, newType string) {
@@ -475,7 +1251,7 @@ func (r *Request) setType(newSetter, newType string) {
 func (r *Request) setBody(setter string, reader io.Reader, len int) {
 	if r.bodysetter != "" {
 		r.chain.fail(
-			"\nambiguous request body contents:\n  set by %s\n  overwritten by %s",
+			"\nambiguous request body contents:\n  already set by %s\n  overwritten by %s",
 			r.bodysetter, setter)
 		return
 	}
@@ -491,7 +1267,17 @@ func (r *Request) setBody(setter string, reader io.Reader, len int) {
 	r.bodysetter = setter
 }
 
+var pathParamRegexp = regexp.MustCompile(`\{[^/{}]+\}`)
+
 func (r *Request) encodeRequest() {
+	if r.http.URL != nil {
+		if param := pathParamRegexp.FindString(r.http.URL.Path); param != "" {
+			r.chain.fail("\nunexpected unsubstituted parameter %s in path:\n  %s",
+				param, r.http.URL.Path)
+			return
+		}
+	}
+
 	if r.query != nil {
 		r.http.URL.RawQuery = r.query.Encode()
 	}
@@ -506,7 +1292,7 @@ func (r *Request) encodeRequest() {
 			return
 		}
 	} else if r.form != nil {
-		r.setBody("WithForm or WithField",
+		r.setBody("WithForm or WithFormField",
 			strings.NewReader(r.form.Encode()), -1)
 	}
 }
@@ -516,24 +1302,428 @@ func (r *Request) sendRequest() (resp *http.Response, elapsed time.Duration) {
 		return
 	}
 
-	for _, printer := range r.config.Printers {
-		printer.Request(&r.http)
+	r.addJarCookies()
+
+	if err := r.http.Context().Err(); err != nil {
+		r.failSend(err)
+		return
 	}
 
-	start := monotime.Now()
+	client, ok := r.getClient()
+	if !ok {
+		return
+	}
 
-	resp, err := r.config.Client.Do(&r.http)
+	var body []byte
 
-	elapsed = monotime.Since(start)
+	if r.retries > 0 && r.http.Body != nil {
+		b, err := ioutil.ReadAll(r.http.Body)
+		if err != nil {
+			r.chain.fail(err.Error())
+			return
+		}
+		body = b
+	}
+
+	delay := r.mindelay
+
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			r.http.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		r.interceptRequest()
+		r.printRequest()
+
+		httpReq := &r.http
+
+		r.trace = nil
+		if r.config.EnableTiming {
+			r.trace = newTimingTrace()
+			httpReq = r.http.WithContext(
+				httptrace.WithClientTrace(r.http.Context(), r.trace.clientTrace()))
+		}
+
+		start := monotime.Now()
+
+		resp, err = client.Do(httpReq)
+
+		elapsed = monotime.Since(start)
+
+		if err == nil {
+			r.interceptResponse(resp)
+			r.printResponse(resp, elapsed)
+		}
+
+		if attempt == r.retries || !r.shouldRetry(resp, err) {
+			break
+		}
+
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+
+		if !r.sleep(delay) {
+			resp, err = nil, r.http.Context().Err()
+			break
+		}
+
+		delay *= 2
+		if delay > r.maxdelay {
+			delay = r.maxdelay
+		}
+	}
 
 	if err != nil {
-		r.chain.fail(err.Error())
-		return
+		r.failSend(err)
+		return nil, elapsed
 	}
 
-	for _, printer := range r.config.Printers {
-		printer.Response(resp, elapsed)
+	r.storeJarCookies(resp)
+
+	if r.config.MaxBodySize > 0 && resp.Body != nil {
+		resp.Body = newLimitedBody(resp.Body, r.config.MaxBodySize)
 	}
 
 	return
 }
+
+func (r *Request) getClient() (Client, bool) {
+	if r.redirpol == DefaultRedirectPolicy && r.maxredirs < 0 &&
+		!r.needsTransport() {
+		return r.config.Client, true
+	}
+
+	httpClient, ok := r.config.Client.(*http.Client)
+	if !ok {
+		r.chain.fail(
+			"\nunexpected Config.Client type for %s:\n  %T\n\n"+
+				"expected:\n  *http.Client", r.clientOptions(), r.config.Client)
+		return nil, false
+	}
+
+	client := *httpClient
+
+	if r.redirpol != DefaultRedirectPolicy {
+		if !r.setRedirectPolicy(&client) {
+			return nil, false
+		}
+	}
+
+	if r.maxredirs >= 0 {
+		r.setMaxRedirects(&client)
+	}
+
+	if r.needsTransport() {
+		if !r.setTransport(&client) {
+			return nil, false
+		}
+	}
+
+	return &client, true
+}
+
+func (r *Request) needsTransport() bool {
+	return r.proxy != nil || r.clientcert != nil
+}
+
+// clientOptions returns names of methods that require
+// Config.Client to be *http.Client, for failure messages
+func (r *Request) clientOptions() string {
+	var names []string
+	if r.redirpol != DefaultRedirectPolicy {
+		names = append(names, "WithRedirectPolicy")
+	}
+	if r.maxredirs >= 0 {
+		names = append(names, "WithMaxRedirects")
+	}
+	if r.proxy != nil {
+		names = append(names, "WithProxy")
+	}
+	if r.clientcert != nil {
+		names = append(names, "WithClientCert")
+	}
+	return strings.Join(names, ", ")
+}
+
+func (r *Request) setRedirectPolicy(client *http.Client) bool {
+	switch r.redirpol {
+	case DontFollowRedirects:
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+
+	case FollowAllRedirects:
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		}
+
+	case FollowSameHostRedirects:
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if req.URL.Host != via[0].URL.Host {
+				return http.ErrUseLastResponse
+			}
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		}
+
+	default:
+		r.chain.fail("\nunexpected redirect policy %d", int(r.redirpol))
+		return false
+	}
+
+	return true
+}
+
+func (r *Request) setMaxRedirects(client *http.Client) {
+	maxredirs := r.maxredirs
+	checkRedirect := client.CheckRedirect
+
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if checkRedirect != nil {
+			if err := checkRedirect(req, via); err != nil {
+				return err
+			}
+		}
+		if len(via) > maxredirs {
+			return fmt.Errorf("stopped after %d redirects", maxredirs)
+		}
+		return nil
+	}
+}
+
+func (r *Request) setTransport(client *http.Client) bool {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	httpTransport, ok := transport.(*http.Transport)
+	if !ok {
+		r.chain.fail(
+			"\nunexpected http.Client transport type for %s:\n  %T\n\n"+
+				"expected:\n  *http.Transport", r.clientOptions(), transport)
+		return false
+	}
+
+	clonedTransport := cloneTransport(httpTransport)
+
+	if r.proxy != nil {
+		clonedTransport.Proxy = http.ProxyURL(r.proxy)
+	}
+
+	if r.clientcert != nil {
+		tlsConfig := &tls.Config{}
+		if clonedTransport.TLSClientConfig != nil {
+			tlsConfig = cloneTLSConfig(clonedTransport.TLSClientConfig)
+		}
+		clonedTransport.TLSClientConfig = tlsConfig
+		tlsConfig.Certificates = append(
+			append([]tls.Certificate(nil), tlsConfig.Certificates...), *r.clientcert)
+	}
+
+	// cloned transport is used for a single request and then dropped,
+	// so don't keep idle connections that nobody would close
+	clonedTransport.DisableKeepAlives = true
+
+	client.Transport = clonedTransport
+
+	return true
+}
+
+// cloneTransport returns a copy of transport without its idle connections;
+// http.Transport.Clone is not available in all supported Go versions, so
+// fields are copied by hand. TLSNextProto isn't copied, since it's bound
+// to the original transport (e.g. registered by http2.ConfigureTransport)
+func cloneTransport(t *http.Transport) *http.Transport {
+	return &http.Transport{
+		Proxy:                  t.Proxy,
+		DialContext:            t.DialContext,
+		Dial:                   t.Dial,
+		DialTLS:                t.DialTLS,
+		TLSClientConfig:        t.TLSClientConfig,
+		TLSHandshakeTimeout:    t.TLSHandshakeTimeout,
+		DisableKeepAlives:      t.DisableKeepAlives,
+		DisableCompression:     t.DisableCompression,
+		MaxIdleConns:           t.MaxIdleConns,
+		MaxIdleConnsPerHost:    t.MaxIdleConnsPerHost,
+		IdleConnTimeout:        t.IdleConnTimeout,
+		ResponseHeaderTimeout:  t.ResponseHeaderTimeout,
+		ExpectContinueTimeout:  t.ExpectContinueTimeout,
+		MaxResponseHeaderBytes: t.MaxResponseHeaderBytes,
+	}
+}
+
+// cloneTLSConfig returns a copy of TLS config; like cloneTransport, it
+// replaces tls.Config.Clone, which is not available in all supported
+// Go versions
+func cloneTLSConfig(c *tls.Config) *tls.Config {
+	return &tls.Config{
+		Rand:                        c.Rand,
+		Time:                        c.Time,
+		Certificates:                c.Certificates,
+		NameToCertificate:           c.NameToCertificate,
+		GetCertificate:              c.GetCertificate,
+		RootCAs:                     c.RootCAs,
+		NextProtos:                  c.NextProtos,
+		ServerName:                  c.ServerName,
+		ClientAuth:                  c.ClientAuth,
+		ClientCAs:                   c.ClientCAs,
+		InsecureSkipVerify:          c.InsecureSkipVerify,
+		CipherSuites:                c.CipherSuites,
+		PreferServerCipherSuites:    c.PreferServerCipherSuites,
+		SessionTicketsDisabled:      c.SessionTicketsDisabled,
+		SessionTicketKey:            c.SessionTicketKey,
+		ClientSessionCache:          c.ClientSessionCache,
+		MinVersion:                  c.MinVersion,
+		MaxVersion:                  c.MaxVersion,
+		CurvePreferences:            c.CurvePreferences,
+		DynamicRecordSizingDisabled: c.DynamicRecordSizingDisabled,
+		Renegotiation:               c.Renegotiation,
+	}
+}
+
+type websocketContextDialer interface {
+	DialContext(ctx context.Context, url string, reqH http.Header) (
+		*websocket.Conn, *http.Response, error)
+}
+
+func (r *Request) sendWebsocketRequest() (
+	resp *http.Response, conn *websocket.Conn, elapsed time.Duration) {
+	if r.chain.failed() {
+		return
+	}
+
+	if r.config.WebsocketDialer == nil {
+		r.chain.fail("\nunexpected nil Config.WebsocketDialer for WebSocket request")
+		return
+	}
+
+	if r.http.Method != "GET" {
+		r.chain.fail("\nunexpected %s method for WebSocket request, expected GET",
+			strconv.Quote(r.http.Method))
+		return
+	}
+
+	if r.bodysetter != "" || r.multipart != nil {
+		r.chain.fail("\nunexpected body for WebSocket request")
+		return
+	}
+
+	r.addJarCookies()
+
+	if err := r.http.Context().Err(); err != nil {
+		r.failSend(err)
+		return
+	}
+
+	u := *r.http.URL
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+
+	r.interceptRequest()
+	r.printRequest()
+
+	start := monotime.Now()
+
+	var err error
+	if dialer, ok := r.config.WebsocketDialer.(websocketContextDialer); ok {
+		conn, resp, err = dialer.DialContext(r.http.Context(), u.String(), r.http.Header)
+	} else {
+		conn, resp, err = r.config.WebsocketDialer.Dial(u.String(), r.http.Header)
+	}
+
+	elapsed = monotime.Since(start)
+
+	if resp != nil {
+		r.interceptResponse(resp)
+		r.printResponse(resp, elapsed)
+	}
+
+	if err != nil && !(err == websocket.ErrBadHandshake && resp != nil) {
+		r.failSend(err)
+		return nil, nil, elapsed
+	}
+
+	r.storeJarCookies(resp)
+
+	return resp, conn, elapsed
+}
+
+func (r *Request) addJarCookies() {
+	if r.config.Jar != nil {
+		for _, c := range r.config.Jar.Cookies(r.http.URL) {
+			r.http.AddCookie(c)
+		}
+	}
+}
+
+func (r *Request) storeJarCookies(resp *http.Response) {
+	if r.config.Jar != nil {
+		if cookies := resp.Cookies(); len(cookies) != 0 {
+			r.config.Jar.SetCookies(r.http.URL, cookies)
+		}
+	}
+}
+
+func (r *Request) sleep(delay time.Duration) bool {
+	select {
+	case <-r.http.Context().Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+func (r *Request) shouldRetry(resp *http.Response, err error) bool {
+	var (
+		isTemporaryNetworkError bool
+		isServerError           bool
+		isClientError           bool
+	)
+
+	if netErr, ok := err.(net.Error); ok {
+		isTemporaryNetworkError = netErr.Temporary() || netErr.Timeout()
+	}
+
+	if err == nil && resp != nil {
+		isServerError = resp.StatusCode >= 500 && resp.StatusCode <= 599
+		isClientError = resp.StatusCode >= 400 && resp.StatusCode <= 499
+	}
+
+	switch r.retrypol {
+	case RetryTemporaryNetworkErrors:
+		return isTemporaryNetworkError
+
+	case RetryTemporaryNetworkAndServerErrors:
+		return isTemporaryNetworkError || isServerError
+
+	case RetryAllErrors:
+		return err != nil || isServerError || isClientError
+	}
+
+	return false
+}
+
+func (r *Request) failSend(err error) {
+	if r.http.Context().Err() == context.DeadlineExceeded {
+		if r.timeout > 0 {
+			r.chain.fail("\nrequest timed out after %s:\n  %s", r.timeout, err.Error())
+		} else {
+			r.chain.fail("\nrequest timed out:\n  %s", err.Error())
+		}
+		return
+	}
+	r.chain.fail(err.Error())
+}
@@ -2,15 +2,24 @@ package httpexpect
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRequestFailed(t *testing.T) {
@@ -159,83 +168,1058 @@ func TestRequestURLQuery(t *testing.T) {
 		WithQueryObject(func() {}).chain.assertFailed(t)
 }
 
+func TestRequestURLQueryRepeated(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req1 := NewRequest(config, "METHOD", "http://example.com/path?aa=foo").
+		WithQuery("aa", "bar").
+		WithQuery("bb", 123).
+		WithQuery("bb", 456)
+
+	type S struct {
+		Bb []int `url:"bb"`
+	}
+
+	req2 := NewRequest(config, "METHOD", "http://example.com/path?aa=foo").
+		WithQueryObject(map[string]interface{}{
+			"aa": "bar",
+		}).
+		WithQueryObject(S{[]int{123, 456}})
+
+	for _, req := range []*Request{req1, req2} {
+		client.req = nil
+
+		req.Expect()
+		req.chain.assertOK(t)
+		assert.Equal(t, "http://example.com/path?aa=foo&aa=bar&bb=123&bb=456",
+			client.req.URL.String())
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	req := NewRequest(config, "GET", "url").WithContext(ctx)
+
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "value", client.req.Context().Value(ctxKey{}))
+}
+
+func TestRequestContextCancelled(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := NewRequest(config, "GET", "url").WithContext(ctx)
+
+	resp := req.Expect()
+	resp.chain.assertFailed(t)
+
+	assert.True(t, client.req == nil)
+	assert.True(t, resp.Raw() == nil)
+}
+
+func TestRequestContextNil(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "GET", "url").WithContext(nil)
+	req.chain.assertFailed(t)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	req := NewRequest(config, "GET", "url").
+		WithTimeout(time.Hour).
+		WithContext(ctx)
+
+	req.Expect().chain.assertOK(t)
+
+	deadline, ok := client.req.Context().Deadline()
+	assert.True(t, ok)
+	assert.True(t, deadline.After(time.Now()))
+
+	assert.Equal(t, "value", client.req.Context().Value(ctxKey{}))
+}
+
+func TestRequestTimeoutExpired(t *testing.T) {
+	client := &mockBlockingClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "GET", "url").
+		WithTimeout(time.Millisecond)
+
+	resp := req.Expect()
+	resp.chain.assertFailed(t)
+
+	assert.True(t, resp.Raw() == nil)
+}
+
+func TestRequestRetry(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	tests := []struct {
+		policy   RetryPolicy
+		err      error
+		status   int
+		attempts int
+	}{
+		{DontRetry, mockNetError{true}, 0, 1},
+		{DontRetry, nil, http.StatusServiceUnavailable, 1},
+		{RetryTemporaryNetworkErrors, mockNetError{true}, 0, 3},
+		{RetryTemporaryNetworkErrors, mockNetError{false}, 0, 1},
+		{RetryTemporaryNetworkErrors, nil, http.StatusServiceUnavailable, 1},
+		{RetryTemporaryNetworkAndServerErrors, mockNetError{true}, 0, 3},
+		{RetryTemporaryNetworkAndServerErrors, nil, http.StatusServiceUnavailable, 3},
+		{RetryTemporaryNetworkAndServerErrors, nil, http.StatusBadRequest, 1},
+		{RetryAllErrors, mockNetError{false}, 0, 3},
+		{RetryAllErrors, errors.New("error"), 0, 3},
+		{RetryAllErrors, nil, http.StatusBadRequest, 3},
+	}
+
+	for _, test := range tests {
+		client := &mockRetryClient{
+			failures: 2,
+			err:      test.err,
+			status:   test.status,
+		}
+
+		config := Config{
+			Client:   client,
+			Reporter: reporter,
+		}
+
+		resp := NewRequest(config, "POST", "url").
+			WithRetry(5, test.policy).
+			WithRetryDelay(0, 0).
+			WithText("body").
+			Expect()
+
+		assert.Equal(t, test.attempts, client.attempts)
+
+		for _, b := range client.bodies {
+			assert.Equal(t, "body", b)
+		}
+
+		switch {
+		case test.attempts == 3:
+			resp.chain.assertOK(t)
+			resp.Status(http.StatusOK).chain.assertOK(t)
+		case test.err != nil:
+			resp.chain.assertFailed(t)
+		default:
+			resp.chain.assertOK(t)
+			resp.Status(test.status).chain.assertOK(t)
+		}
+	}
+}
+
+func TestRequestRetryExhausted(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	client1 := &mockRetryClient{
+		failures: 10,
+		err:      mockNetError{true},
+	}
+
+	resp1 := NewRequest(Config{Client: client1, Reporter: reporter}, "GET", "url").
+		WithRetry(2, RetryAllErrors).
+		WithRetryDelay(0, 0).
+		Expect()
+
+	assert.Equal(t, 3, client1.attempts)
+	resp1.chain.assertFailed(t)
+
+	client2 := &mockRetryClient{
+		failures: 10,
+		status:   http.StatusBadGateway,
+	}
+
+	resp2 := NewRequest(Config{Client: client2, Reporter: reporter}, "GET", "url").
+		WithRetry(2, RetryAllErrors).
+		WithRetryDelay(0, 0).
+		Expect()
+
+	assert.Equal(t, 3, client2.attempts)
+	resp2.chain.assertOK(t)
+	resp2.Status(http.StatusBadGateway).chain.assertOK(t)
+}
+
+func TestRequestRetryTimeout(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	client := &mockRetryClient{
+		failures: 10,
+		err:      mockNetError{true},
+	}
+
+	resp := NewRequest(Config{Client: client, Reporter: reporter}, "GET", "url").
+		WithRetry(10, RetryAllErrors).
+		WithRetryDelay(time.Hour, time.Hour).
+		WithTimeout(time.Millisecond * 10).
+		Expect()
+
+	assert.Equal(t, 1, client.attempts)
+	resp.chain.assertFailed(t)
+}
+
+func TestRequestRetryInvalid(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: reporter,
+	}
+
+	NewRequest(config, "GET", "url").
+		WithRetry(-1, RetryAllErrors).chain.assertFailed(t)
+
+	NewRequest(config, "GET", "url").
+		WithRetryDelay(time.Second, time.Millisecond).chain.assertFailed(t)
+}
+
+func TestRequestName(t *testing.T) {
+	logger := &mockLogger{}
+
+	named := &mockNamedPrinter{}
+
+	config := Config{
+		Client: &mockClient{},
+		Printers: []Printer{
+			named,
+			NewCompactPrinter(logger),
+			NewCurlPrinter(logger),
+		},
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "GET", "http://example.com").WithName("foo")
+
+	req1.Expect().chain.assertOK(t)
+
+	req2 := NewRequest(config, "GET", "http://example.com")
+
+	req2.Expect().chain.assertOK(t)
+
+	assert.Equal(t, []string{"foo", ""}, named.requests)
+	assert.Equal(t, []string{"foo", ""}, named.responses)
+
+	assert.Equal(t, 4, len(logger.messages))
+	assert.Equal(t, "[foo] GET http://example.com", logger.messages[0])
+	assert.Equal(t, "GET http://example.com", logger.messages[2])
+}
+
+func TestRequestFactory(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		Client: client,
+		RequestFactory: func(method, url string) (*http.Request, error) {
+			req, err := http.NewRequest(method, url, nil)
+			if err == nil {
+				req.Header.Set("X-Trace", "123")
+			}
+			return req, err
+		},
+		Reporter: newMockReporter(t),
+	}
+
+	req := NewRequest(config, "GET", "http://example.com/path").
+		WithHeader("X-Foo", "bar")
+
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "GET", client.req.Method)
+	assert.Equal(t, "http://example.com/path", client.req.URL.String())
+	assert.Equal(t, "123", client.req.Header.Get("X-Trace"))
+	assert.Equal(t, "bar", client.req.Header.Get("X-Foo"))
+}
+
+func TestRequestFactoryFailed(t *testing.T) {
+	config1 := Config{
+		Client: &mockClient{},
+		RequestFactory: func(method, url string) (*http.Request, error) {
+			return nil, errors.New("error")
+		},
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config1, "GET", "http://example.com")
+	req1.chain.assertFailed(t)
+
+	config2 := Config{
+		Client: &mockClient{},
+		RequestFactory: func(method, url string) (*http.Request, error) {
+			return nil, nil
+		},
+		Reporter: newMockReporter(t),
+	}
+
+	req2 := NewRequest(config2, "GET", "http://example.com")
+	req2.chain.assertFailed(t)
+
+	config3 := Config{
+		Client: &mockClient{},
+		RequestFactory: func(method, url string) (*http.Request, error) {
+			return &http.Request{Method: method}, nil
+		},
+		Reporter: newMockReporter(t),
+	}
+
+	req3 := NewRequest(config3, "GET", "http://example.com")
+	req3.chain.assertFailed(t)
+
+	config4 := Config{
+		Client: &mockClient{},
+		RequestFactory: func(method, u string) (*http.Request, error) {
+			pu, _ := url.Parse(u)
+			return &http.Request{Method: method, URL: pu}, nil
+		},
+		Reporter: newMockReporter(t),
+	}
+
+	req4 := NewRequest(config4, "GET", "http://example.com").WithHeader("a", "b")
+	req4.Expect().chain.assertOK(t)
+}
+
+func TestRequestURLPath(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	config := Config{
+		BaseURL:  "http://example.com",
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req1 := NewRequest(config, "GET", "/users/{id}/posts/{post}").
+		WithPath("id", 123).
+		WithPath("post", "a b/c")
+
+	req1.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "/users/123/posts/a b/c", client.req.URL.Path)
+	assert.Equal(t, "http://example.com/users/123/posts/a%20b%2Fc",
+		client.req.URL.String())
+
+	req2 := NewRequest(config, "GET", "/{x}/{x}?q={x}").
+		WithPath("x", "foo")
+
+	req2.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "http://example.com/foo/foo?q={x}", client.req.URL.String())
+
+	req3 := NewRequest(config, "GET", "/files/{name}").
+		WithPath("name", "a;b,c?d%e")
+
+	req3.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "/files/a;b,c?d%e", client.req.URL.Path)
+	assert.Equal(t, "http://example.com/files/a%3Bb%2Cc%3Fd%25e",
+		client.req.URL.String())
+}
+
+func TestRequestURLPathFailed(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "GET", "/users/{id}").WithPath("name", "foo")
+	req1.chain.assertFailed(t)
+
+	req2 := NewRequest(config, "GET", "/users/{id}").WithPath("id", nil)
+	req2.chain.assertFailed(t)
+
+	req3 := NewRequest(config, "GET", "/users/{id}/{name}").WithPath("id", 1)
+	req3.chain.assertOK(t)
+
+	req3.Expect().chain.assertFailed(t)
+	assert.Nil(t, client.req)
+}
+
+func TestRequestExpectUntil(t *testing.T) {
+	client := &mockPollClient{ready: 3}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "POST", "url").WithText("body")
+
+	resp := req.ExpectUntil(time.Second, time.Millisecond,
+		func(r *Response) bool {
+			r.JSON().Object().ValueEqual("status", "done")
+			return r.JSON().Object().Value("status").Raw() == "done"
+		})
+
+	resp.chain.assertOK(t)
+	req.chain.assertOK(t)
+
+	assert.False(t, reporter.reported)
+	assert.Equal(t, 3, client.attempts)
+	assert.Equal(t, []string{"body", "body", "body"}, client.bodies)
+
+	resp.JSON().Object().ValueEqual("status", "done")
+	resp.chain.assertOK(t)
+}
+
+func TestRequestExpectUntilTimeout(t *testing.T) {
+	client := &mockPollClient{ready: 1000}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	req := NewRequest(config, "GET", "url")
+
+	resp := req.ExpectUntil(time.Millisecond*50, time.Millisecond*10,
+		func(r *Response) bool {
+			return r.JSON().Object().Value("status").Raw() == "done"
+		})
+
+	resp.chain.assertFailed(t)
+	req.chain.assertFailed(t)
+
+	assert.True(t, client.attempts > 1)
+	assert.True(t, client.attempts < 10)
+
+	resp.JSON().Object().ValueEqual("status", "pending")
+}
+
+func TestRequestExpectUntilJar(t *testing.T) {
+	var cookies []string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookies = append(cookies, r.Header.Get("Cookie"))
+		http.SetCookie(w, &http.Cookie{
+			Name:  "b",
+			Value: strconv.Itoa(len(cookies)),
+		})
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := Config{
+		BaseURL:  server.URL,
+		Client:   &http.Client{},
+		Jar:      NewJar(),
+		Reporter: newMockReporter(t),
+	}
+
+	u, _ := url.Parse(server.URL)
+	config.Jar.SetCookies(u, []*http.Cookie{{Name: "a", Value: "1"}})
+
+	req := NewRequest(config, "GET", "/").WithCookie("c", "3")
+
+	resp := req.ExpectUntil(time.Second, time.Millisecond,
+		func(r *Response) bool {
+			return len(cookies) == 3
+		})
+
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, []string{
+		"c=3; a=1",
+		"c=3; a=1; b=1",
+		"c=3; a=1; b=2",
+	}, cookies)
+}
+
+func TestRequestExpectUntilFailed(t *testing.T) {
+	config := Config{
+		Client:   &mockClient{err: errors.New("error")},
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "GET", "url")
+
+	resp1 := req1.ExpectUntil(time.Second, time.Millisecond,
+		func(r *Response) bool {
+			return true
+		})
+	resp1.chain.assertFailed(t)
+
+	req2 := NewRequest(config, "GET", "url")
+
+	resp2 := req2.ExpectUntil(time.Second, time.Millisecond, nil)
+	resp2.chain.assertFailed(t)
+}
+
+func TestRequestRedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}))
+	defer other.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/same", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/other", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := Config{
+		BaseURL:  server.URL,
+		Client:   &http.Client{},
+		Reporter: NewAssertReporter(t),
+	}
+
+	cases := []struct {
+		policy      RedirectPolicy
+		sameStatus  int
+		otherStatus int
+	}{
+		{DefaultRedirectPolicy, http.StatusOK, http.StatusAccepted},
+		{DontFollowRedirects, http.StatusFound, http.StatusFound},
+		{FollowAllRedirects, http.StatusOK, http.StatusAccepted},
+		{FollowSameHostRedirects, http.StatusOK, http.StatusFound},
+	}
+
+	for _, tc := range cases {
+		NewRequest(config, "GET", "/same").
+			WithRedirectPolicy(tc.policy).
+			Expect().
+			Status(tc.sameStatus)
+
+		NewRequest(config, "GET", "/other").
+			WithRedirectPolicy(tc.policy).
+			Expect().
+			Status(tc.otherStatus)
+	}
+
+	assert.Nil(t, config.Client.(*http.Client).CheckRedirect)
+}
+
+func TestRequestRedirectPolicyFailed(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "GET", "url").
+		WithRedirectPolicy(DefaultRedirectPolicy)
+	req1.Expect().chain.assertOK(t)
+
+	req2 := NewRequest(config, "GET", "url").
+		WithRedirectPolicy(DontFollowRedirects)
+	req2.Expect().chain.assertFailed(t)
+
+	config.Client = &http.Client{}
+
+	req3 := NewRequest(config, "GET", "url").
+		WithRedirectPolicy(RedirectPolicy(100))
+	req3.Expect().chain.assertFailed(t)
+}
+
+func TestRequestMaxRedirects(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		if n > 0 {
+			http.Redirect(w, r, "/"+strconv.Itoa(n-1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		BaseURL:  server.URL,
+		Client:   &http.Client{},
+		Reporter: reporter,
+	}
+
+	NewRequest(config, "GET", "/3").
+		WithMaxRedirects(3).
+		Expect().
+		Status(http.StatusOK).
+		chain.assertOK(t)
+
+	NewRequest(config, "GET", "/0").
+		WithMaxRedirects(0).
+		Expect().
+		Status(http.StatusOK).
+		chain.assertOK(t)
+
+	NewRequest(config, "GET", "/4").
+		WithMaxRedirects(3).
+		Expect().
+		chain.assertFailed(t)
+
+	NewRequest(config, "GET", "/1").
+		WithMaxRedirects(0).
+		Expect().
+		chain.assertFailed(t)
+
+	NewRequest(config, "GET", "/4").
+		WithRedirectPolicy(DontFollowRedirects).
+		WithMaxRedirects(0).
+		Expect().
+		Status(http.StatusFound).
+		chain.assertOK(t)
+
+	NewRequest(config, "GET", "/20").
+		WithRedirectPolicy(FollowAllRedirects).
+		WithMaxRedirects(15).
+		Expect().
+		chain.assertFailed(t)
+
+	assert.Nil(t, config.Client.(*http.Client).CheckRedirect)
+
+	req := NewRequest(config, "GET", "/1").WithMaxRedirects(-1)
+	req.chain.assertFailed(t)
+
+	config.Client = &mockClient{}
+
+	NewRequest(config, "GET", "/1").
+		WithMaxRedirects(1).
+		Expect().
+		chain.assertFailed(t)
+}
+
+func TestRequestProxy(t *testing.T) {
+	keepAlive := true
+
+	proxy := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			keepAlive = !r.Close
+			w.Write([]byte("proxied " + r.URL.String()))
+		}))
+	defer proxy.Close()
+
+	client := &http.Client{}
+
+	config := Config{
+		Client:   client,
+		Reporter: NewAssertReporter(t),
+	}
+
+	NewRequest(config, "GET", "http://example.com/path").
+		WithProxy(proxy.URL).
+		Expect().
+		Status(http.StatusOK).
+		Text().Equal("proxied http://example.com/path")
+
+	assert.Nil(t, client.Transport)
+	assert.False(t, keepAlive)
+}
+
+func TestRequestProxyFailed(t *testing.T) {
+	config := Config{
+		Client:   &http.Client{},
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "GET", "url").WithProxy("://bad")
+	req1.chain.assertFailed(t)
+
+	req2 := NewRequest(config, "GET", "url").WithProxy("localhost")
+	req2.chain.assertFailed(t)
+
+	config.Client = &mockClient{}
+
+	req3 := NewRequest(config, "GET", "url").WithProxy("http://localhost")
+	req3.chain.assertOK(t)
+	req3.Expect().chain.assertFailed(t)
+
+	config.Client = &http.Client{Transport: &mockTransport{}}
+
+	req4 := NewRequest(config, "GET", "url").WithProxy("http://localhost")
+	req4.Expect().chain.assertFailed(t)
+}
+
+func TestRequestClientCert(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strconv.Itoa(len(r.TLS.PeerCertificates))))
+		}))
+
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	cert, err := x509.ParseCertificate(server.TLS.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+
+	config := Config{
+		BaseURL:  server.URL,
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	NewRequest(config, "GET", "/").
+		Expect().chain.assertFailed(t)
+
+	NewRequest(config, "GET", "/").
+		WithClientCert(server.TLS.Certificates[0]).
+		Expect().
+		Status(http.StatusOK).
+		Text().Equal("1").chain.assertOK(t)
+
+	assert.Empty(t, client.Transport.(*http.Transport).TLSClientConfig.Certificates)
+
+	config.Client = &mockClient{}
+
+	NewRequest(config, "GET", "/").
+		WithClientCert(server.TLS.Certificates[0]).
+		Expect().chain.assertFailed(t)
+}
+
 func TestRequestURLConcat(t *testing.T) {
 	client := &mockClient{}
 
 	reporter := NewAssertReporter(t)
 
-	var reqs [5]*Request
-
-	config1 := Config{
-		BaseURL:  "",
+	var reqs [5]*Request
+
+	config1 := Config{
+		BaseURL:  "",
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	reqs[0] = NewRequest(config1, "METHOD", "http://example.com/path")
+
+	config2 := Config{
+		BaseURL:  "http://example.com",
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	reqs[1] = NewRequest(config2, "METHOD", "path")
+	reqs[2] = NewRequest(config2, "METHOD", "/path")
+
+	config3 := Config{
+		BaseURL:  "http://example.com/",
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	reqs[3] = NewRequest(config3, "METHOD", "path")
+	reqs[4] = NewRequest(config3, "METHOD", "/path")
+
+	for _, req := range reqs {
+		assert.Equal(t, "http://example.com/path", req.http.URL.String())
+	}
+
+	empty1 := NewRequest(config1, "METHOD", "")
+	empty2 := NewRequest(config2, "METHOD", "")
+	empty3 := NewRequest(config3, "METHOD", "")
+
+	assert.Equal(t, "", empty1.http.URL.String())
+	assert.Equal(t, "http://example.com", empty2.http.URL.String())
+	assert.Equal(t, "http://example.com/", empty3.http.URL.String())
+}
+
+func TestRequestURLFormat(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	var reqs [3]*Request
+
+	config1 := Config{
+		BaseURL:  "http://example.com/",
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	reqs[0] = NewRequest(config1, "METHOD", "/foo/%s", "bar")
+	reqs[1] = NewRequest(config1, "METHOD", "%sfoo%s", "/", "/bar")
+	reqs[2] = NewRequest(config1, "%s", "/foo/bar")
+
+	for _, req := range reqs {
+		assert.Equal(t, "http://example.com/foo/bar", req.http.URL.String())
+	}
+
+	config2 := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	r := NewRequest(config2, "GET", "%s", nil)
+
+	r.chain.assertFailed(t)
+}
+
+func TestRequestHeaders(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+
+	req.WithHeader("First-Header", "foo")
+
+	req.WithHeaders(map[string]string{
+		"Second-Header": "bar",
+		"Content-Type":  "baz",
+		"Host":          "example.com",
+	})
+
+	expectedHeaders := map[string][]string{
+		"First-Header":  {"foo"},
+		"Second-Header": {"bar"},
+		"Content-Type":  {"baz"},
+	}
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "METHOD", client.req.Method)
+	assert.Equal(t, "example.com", client.req.Host)
+	assert.Equal(t, "url", client.req.URL.String())
+	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
+
+	assert.Equal(t, &client.resp, resp.Raw())
+}
+
+func TestRequestHeadersReplace(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+
+	req.WithHeader("First-Header", "foo")
+	req.WithHeader("first-header", "bar")
+
+	req.WithHeaders(map[string]string{
+		"Second-Header": "baz",
+	})
+	req.WithHeaders(map[string]string{
+		"Second-Header": "qux",
+	})
+
+	expectedHeaders := map[string][]string{
+		"First-Header":  {"bar"},
+		"Second-Header": {"qux"},
+	}
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
+}
+
+func TestRequestHost(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	config := Config{
 		Client:   client,
 		Reporter: reporter,
 	}
 
-	reqs[0] = NewRequest(config1, "METHOD", "http://example.com/path")
+	req := NewRequest(config, "GET", "http://127.0.0.1/path").
+		WithHost("example.com")
 
-	config2 := Config{
-		BaseURL:  "http://example.com",
-		Client:   client,
-		Reporter: reporter,
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "example.com", client.req.Host)
+	assert.Equal(t, "127.0.0.1", client.req.URL.Host)
+	assert.Equal(t, "", client.req.Header.Get("Host"))
+}
+
+func TestRequestHeadersConfig(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		Client: client,
+		Headers: map[string]string{
+			"Accept":     "application/json",
+			"X-Test-Run": "123",
+			"Host":       "example.org",
+		},
+		Reporter: newMockReporter(t),
 	}
 
-	reqs[1] = NewRequest(config2, "METHOD", "path")
-	reqs[2] = NewRequest(config2, "METHOD", "/path")
+	req1 := NewRequest(config, "GET", "url")
 
-	config3 := Config{
-		BaseURL:  "http://example.com/",
+	req1.Expect().chain.assertOK(t)
+
+	assert.Equal(t, http.Header{
+		"Accept":     {"application/json"},
+		"X-Test-Run": {"123"},
+	}, client.req.Header)
+	assert.Equal(t, "example.org", client.req.Host)
+
+	req2 := NewRequest(config, "GET", "url").
+		WithHeader("X-Test-Run", "456").
+		WithHeader("Host", "example.com")
+
+	req2.Expect().chain.assertOK(t)
+
+	assert.Equal(t, http.Header{
+		"Accept":     {"application/json"},
+		"X-Test-Run": {"456"},
+	}, client.req.Header)
+	assert.Equal(t, "example.com", client.req.Host)
+}
+
+func TestRequestCookies(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
 		Client:   client,
 		Reporter: reporter,
 	}
 
-	reqs[3] = NewRequest(config3, "METHOD", "path")
-	reqs[4] = NewRequest(config3, "METHOD", "/path")
+	req := NewRequest(config, "METHOD", "url")
 
-	for _, req := range reqs {
-		assert.Equal(t, "http://example.com/path", req.http.URL.String())
+	req.WithCookie("foo", "1")
+	req.WithCookie("bar", "2 ")
+
+	req.WithCookies(map[string]string{
+		"baz": "3",
+	})
+
+	expectedHeaders := map[string][]string{
+		"Cookie": {`foo=1; bar="2 "; baz=3`},
 	}
 
-	empty1 := NewRequest(config1, "METHOD", "")
-	empty2 := NewRequest(config2, "METHOD", "")
-	empty3 := NewRequest(config3, "METHOD", "")
+	resp := req.Expect()
+	resp.chain.assertOK(t)
 
-	assert.Equal(t, "", empty1.http.URL.String())
-	assert.Equal(t, "http://example.com", empty2.http.URL.String())
-	assert.Equal(t, "http://example.com/", empty3.http.URL.String())
+	assert.Equal(t, "METHOD", client.req.Method)
+	assert.Equal(t, "url", client.req.URL.String())
+	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
 }
 
-func TestRequestURLFormat(t *testing.T) {
-	client := &mockClient{}
+func TestRequestDisableDecompression(t *testing.T) {
+	var body bytes.Buffer
 
-	reporter := NewAssertReporter(t)
+	gw := gzip.NewWriter(&body)
+	gw.Write([]byte("hello"))
+	gw.Close()
 
-	var reqs [3]*Request
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
 
 	config1 := Config{
-		BaseURL:  "http://example.com/",
 		Client:   client,
 		Reporter: reporter,
 	}
 
-	reqs[0] = NewRequest(config1, "METHOD", "/foo/%s", "bar")
-	reqs[1] = NewRequest(config1, "METHOD", "%sfoo%s", "/", "/bar")
-	reqs[2] = NewRequest(config1, "%s", "/foo/bar")
+	req1 := NewRequest(config1, "POST", "url").
+		WithHeader("Content-Encoding", "gzip").
+		WithBytes(body.Bytes())
 
-	for _, req := range reqs {
-		assert.Equal(t, "http://example.com/foo/bar", req.http.URL.String())
-	}
+	req1.Expect().Body().Equal("hello")
+	req1.chain.assertOK(t)
 
 	config2 := Config{
-		Reporter: newMockReporter(t),
+		Client:               client,
+		Reporter:             reporter,
+		DisableDecompression: true,
 	}
 
-	r := NewRequest(config2, "GET", "%s", nil)
+	req2 := NewRequest(config2, "POST", "url").
+		WithHeader("Content-Encoding", "gzip").
+		WithBytes(body.Bytes())
 
-	r.chain.assertFailed(t)
+	req2.Expect().Body().Equal(body.String())
+	req2.chain.assertOK(t)
 }
 
-func TestRequestHeaders(t *testing.T) {
+func TestRequestBasicAuth(t *testing.T) {
 	client := &mockClient{}
 
 	reporter := newMockReporter(t)
@@ -247,29 +1231,65 @@ func TestRequestHeaders(t *testing.T) {
 
 	req := NewRequest(config, "METHOD", "url")
 
-	req.WithHeader("First-Header", "foo")
+	req.WithBasicAuth("Aladdin", "open sesame")
+	req.chain.assertOK(t)
 
-	req.WithHeaders(map[string]string{
-		"Second-Header": "bar",
-		"Content-Type":  "baz",
-		"Host":          "example.com",
-	})
+	assert.Equal(t, "Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ==",
+		req.http.Header.Get("Authorization"))
+}
 
-	expectedHeaders := map[string][]string{
-		"First-Header":  {"foo"},
-		"Second-Header": {"bar"},
-		"Content-Type":  {"baz"},
+func TestRequestBasicAuthFromEnv(t *testing.T) {
+	os.Setenv("HTTPEXPECT_TEST_USER", "Aladdin")
+	os.Setenv("HTTPEXPECT_TEST_PASS", "open sesame")
+	defer os.Unsetenv("HTTPEXPECT_TEST_USER")
+	defer os.Unsetenv("HTTPEXPECT_TEST_PASS")
+
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
 	}
 
-	resp := req.Expect()
-	resp.chain.assertOK(t)
+	req1 := NewRequest(config, "METHOD", "url")
 
-	assert.Equal(t, "METHOD", client.req.Method)
-	assert.Equal(t, "example.com", client.req.Host)
-	assert.Equal(t, "url", client.req.URL.String())
-	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
+	req1.WithBasicAuthFromEnv("HTTPEXPECT_TEST_USER", "HTTPEXPECT_TEST_PASS")
+	req1.chain.assertOK(t)
 
-	assert.Equal(t, &client.resp, resp.Raw())
+	assert.Equal(t, "Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ==",
+		req1.http.Header.Get("Authorization"))
+
+	req2 := NewRequest(config, "METHOD", "url")
+
+	req2.WithBasicAuthFromEnv("HTTPEXPECT_TEST_MISSING", "HTTPEXPECT_TEST_PASS")
+	req2.chain.assertFailed(t)
+
+	assert.Equal(t, "", req2.http.Header.Get("Authorization"))
+
+	req3 := NewRequest(config, "METHOD", "url")
+
+	req3.WithBasicAuthFromEnv("HTTPEXPECT_TEST_USER", "HTTPEXPECT_TEST_MISSING")
+	req3.chain.assertFailed(t)
+
+	assert.Equal(t, "", req3.http.Header.Get("Authorization"))
+}
+
+func TestRequestBearer(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "METHOD", "url")
+
+	req.WithBasicAuth("Aladdin", "open sesame")
+	req.WithBearer("mF_9.B5f-4.1JqM")
+	req.chain.assertOK(t)
+
+	assert.Equal(t, []string{"Bearer mF_9.B5f-4.1JqM"},
+		req.http.Header["Authorization"])
 }
 
 func TestRequestBodyReader(t *testing.T) {
@@ -295,7 +1315,7 @@ func TestRequestBodyReader(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, make(http.Header), client.req.Header)
-	assert.Equal(t, "body", string(resp.content))
+	assert.Equal(t, "body", string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -321,6 +1341,146 @@ func TestRequestBodyReaderNil(t *testing.T) {
 	assert.Equal(t, int64(0), client.req.ContentLength)
 }
 
+func TestRequestBodyChunked(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req := NewRequest(config, "GET", "url").
+		WithChunked(bytes.NewBufferString("body"))
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, int64(-1), client.req.ContentLength)
+	assert.Equal(t, []string{"chunked"}, client.req.TransferEncoding)
+
+	assert.Equal(t, "body", string(resp.content.data))
+}
+
+func TestRequestBodyChunkedFailed(t *testing.T) {
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	}
+
+	req1 := NewRequest(config, "GET", "url").WithChunked(nil)
+	req1.chain.assertFailed(t)
+
+	req2 := NewRequest(config, "GET", "url").
+		WithJSON(map[string]string{"a": "b"}).
+		WithChunked(bytes.NewBufferString("body"))
+	req2.chain.assertFailed(t)
+	assert.Nil(t, req2.http.TransferEncoding)
+}
+
+func TestRequestMaxBodySize(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:      &mockClient{},
+		Reporter:    reporter,
+		MaxBodySize: 4,
+	}
+
+	resp1 := NewRequest(config, "GET", "url").
+		WithText("body").
+		Expect()
+	resp1.chain.assertOK(t)
+	assert.Equal(t, "body", string(resp1.content.data))
+
+	resp2 := NewRequest(config, "GET", "url").
+		WithText("body2").
+		Expect()
+	resp2.chain.assertFailed(t)
+
+	config.MaxBodySize = 0
+
+	resp3 := NewRequest(config, "GET", "url").
+		WithText("body2").
+		Expect()
+	resp3.chain.assertOK(t)
+	assert.Equal(t, "body2", string(resp3.content.data))
+}
+
+func TestRequestStreaming(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: reporter,
+	}
+
+	resp1 := NewRequest(config, "GET", "url").
+		WithStreaming().
+		WithJSON([]int{1, 2, 3}).
+		Expect()
+	resp1.chain.assertOK(t)
+	assert.Equal(t, contentPending, resp1.content.state)
+
+	resp1.JSONStream().Count().Equal(3)
+	resp1.chain.assertOK(t)
+	assert.Nil(t, resp1.content.data)
+
+	resp2 := NewRequest(config, "GET", "url").
+		WithStreaming().
+		WithJSON([]int{1, 2, 3}).
+		Expect()
+
+	resp2.JSON().Array().Elements(1, 2, 3)
+	resp2.JSONStream().Count().Equal(3)
+	resp2.chain.assertOK(t)
+
+	config.MaxBodySize = 4
+
+	resp3 := NewRequest(config, "GET", "url").
+		WithStreaming().
+		WithJSON([]int{1, 2, 3}).
+		Expect()
+	resp3.chain.assertOK(t)
+
+	resp3.JSONStream().Count().chain.assertFailed(t)
+}
+
+func TestRequestMaxBodySizeDecompressed(t *testing.T) {
+	var body bytes.Buffer
+
+	gw := gzip.NewWriter(&body)
+	gw.Write(bytes.Repeat([]byte("a"), 1024*1024))
+	gw.Close()
+
+	assert.True(t, body.Len() < 16*1024)
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:      &mockClient{},
+		Reporter:    reporter,
+		MaxBodySize: 16 * 1024,
+	}
+
+	resp1 := NewRequest(config, "POST", "url").
+		WithHeader("Content-Encoding", "gzip").
+		WithBytes(body.Bytes()).
+		Expect()
+	resp1.chain.assertFailed(t)
+	assert.Nil(t, resp1.content.data)
+
+	config.MaxBodySize = 1024 * 1024
+
+	resp2 := NewRequest(config, "POST", "url").
+		WithHeader("Content-Encoding", "gzip").
+		WithBytes(body.Bytes()).
+		Expect()
+	resp2.chain.assertOK(t)
+	assert.Equal(t, 1024*1024, len(resp2.content.data))
+}
+
 func TestRequestBodyBytes(t *testing.T) {
 	client := &mockClient{}
 
@@ -344,7 +1504,7 @@ func TestRequestBodyBytes(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, make(http.Header), client.req.Header)
-	assert.Equal(t, "body", string(resp.content))
+	assert.Equal(t, "body", string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -370,6 +1530,72 @@ func TestRequestBodyBytesNil(t *testing.T) {
 	assert.Equal(t, int64(0), client.req.ContentLength)
 }
 
+func TestRequestBodyFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bytesPath := filepath.Join(dir, "payload.bin")
+	jsonPath := filepath.Join(dir, "payload.json")
+	badPath := filepath.Join(dir, "bad.json")
+
+	ioutil.WriteFile(bytesPath, []byte("body"), 0644)
+	ioutil.WriteFile(jsonPath, []byte(`{"key": "value"}`), 0644)
+	ioutil.WriteFile(badPath, []byte(`{"key":`), 0644)
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	resp := NewRequest(config, "METHOD", "url").
+		WithBytesFromFile(bytesPath).
+		Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, int64(len("body")), client.req.ContentLength)
+	assert.Equal(t, make(http.Header), client.req.Header)
+	assert.Equal(t, "body", string(resp.content.data))
+
+	resp = NewRequest(config, "METHOD", "url").
+		WithJSONFromFile(jsonPath).
+		Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, http.Header{
+		"Content-Type": {"application/json; charset=utf-8"},
+	}, client.req.Header)
+	assert.Equal(t, `{"key": "value"}`, string(resp.content.data))
+
+	req := NewRequest(config, "METHOD", "url").
+		WithBytesFromFile(filepath.Join(dir, "missing"))
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "METHOD", "url").
+		WithJSONFromFile(filepath.Join(dir, "missing"))
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "METHOD", "url").
+		WithJSONFromFile(badPath)
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "METHOD", "url").
+		WithText("text").
+		WithJSONFromFile(jsonPath)
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "METHOD", "url").
+		WithBytesFromFile(bytesPath).
+		WithBytes([]byte("body"))
+	req.chain.assertFailed(t)
+}
+
 func TestRequestBodyText(t *testing.T) {
 	client := &mockClient{}
 
@@ -399,7 +1625,7 @@ func TestRequestBodyText(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
-	assert.Equal(t, "some text", string(resp.content))
+	assert.Equal(t, "some text", string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -436,7 +1662,7 @@ func TestRequestBodyForm(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
-	assert.Equal(t, `a=1&b=2`, string(resp.content))
+	assert.Equal(t, `a=1&b=2`, string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -462,8 +1688,8 @@ func TestRequestBodyField(t *testing.T) {
 		"Some-Header": "foo",
 	})
 
-	req.WithField("a", 1)
-	req.WithField("b", "2")
+	req.WithFormField("a", 1)
+	req.WithFormField("b", "2")
 
 	resp := req.Expect()
 	resp.chain.assertOK(t)
@@ -471,7 +1697,7 @@ func TestRequestBodyField(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
-	assert.Equal(t, `a=1&b=2`, string(resp.content))
+	assert.Equal(t, `a=1&b=2`, string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -506,7 +1732,7 @@ func TestRequestBodyFormStruct(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
-	assert.Equal(t, `a=1&b=2`, string(resp.content))
+	assert.Equal(t, `a=1&b=2`, string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -533,7 +1759,7 @@ func TestRequestBodyFormCombined(t *testing.T) {
 
 	req.WithForm(S{A: 1})
 	req.WithForm(map[string]string{"b": "2"})
-	req.WithField("c", 3)
+	req.WithFormField("c", 3)
 
 	resp := req.Expect()
 	resp.chain.assertOK(t)
@@ -541,7 +1767,7 @@ func TestRequestBodyFormCombined(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
-	assert.Equal(t, `a=1&b=2&c=3`, string(resp.content))
+	assert.Equal(t, `a=1&b=2&c=3`, string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
@@ -560,7 +1786,7 @@ func TestRequestBodyMultipart(t *testing.T) {
 
 	req.WithMultipart()
 	req.WithForm(map[string]string{"b": "1", "c": "2"})
-	req.WithField("a", 3)
+	req.WithFormField("a", 3)
 
 	resp := req.Expect()
 	resp.chain.assertOK(t)
@@ -574,7 +1800,7 @@ func TestRequestBodyMultipart(t *testing.T) {
 	assert.Equal(t, "multipart/form-data", mediatype)
 	assert.True(t, params["boundary"] != "")
 
-	reader := multipart.NewReader(bytes.NewReader(resp.content), params["boundary"])
+	reader := multipart.NewReader(bytes.NewReader(resp.content.data), params["boundary"])
 
 	part1, _ := reader.NextPart()
 	assert.Equal(t, "b", part1.FormName())
@@ -634,7 +1860,7 @@ func TestRequestBodyMultipartFile(t *testing.T) {
 	assert.Equal(t, "multipart/form-data", mediatype)
 	assert.True(t, params["boundary"] != "")
 
-	reader := multipart.NewReader(bytes.NewReader(resp.content), params["boundary"])
+	reader := multipart.NewReader(bytes.NewReader(resp.content.data), params["boundary"])
 
 	part1, _ := reader.NextPart()
 	assert.Equal(t, "a", part1.FormName())
@@ -644,7 +1870,9 @@ func TestRequestBodyMultipartFile(t *testing.T) {
 
 	part2, _ := reader.NextPart()
 	assert.Equal(t, "b", part2.FormName())
-	assert.Equal(t, filename2, part2.FileName())
+	// newer Go versions strip directory in Part.FileName, so check the header
+	_, disposition, _ := mime.ParseMediaType(part2.Header.Get("Content-Disposition"))
+	assert.Equal(t, filename2, disposition["filename"])
 	b2, _ := ioutil.ReadAll(part2)
 	assert.Equal(t, "2", string(b2))
 
@@ -693,11 +1921,39 @@ func TestRequestBodyJSON(t *testing.T) {
 	assert.Equal(t, "METHOD", client.req.Method)
 	assert.Equal(t, "url", client.req.URL.String())
 	assert.Equal(t, http.Header(expectedHeaders), client.req.Header)
-	assert.Equal(t, `{"key":"value"}`, string(resp.content))
+	assert.Equal(t, `{"key":"value"}`, string(resp.content.data))
 
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
+func TestRequestBodyJSONContentType(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	req1 := NewRequest(config, "METHOD", "url")
+
+	req1.WithJSON(map[string]interface{}{"key": "value"}, "application/vnd.api+json")
+
+	resp := req1.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, http.Header{
+		"Content-Type": {"application/vnd.api+json"},
+	}, client.req.Header)
+	assert.Equal(t, `{"key":"value"}`, string(resp.content.data))
+
+	req2 := NewRequest(config, "METHOD", "url")
+
+	req2.WithJSON(map[string]interface{}{"key": "value"}, "a", "b")
+	req2.chain.assertFailed(t)
+}
+
 func TestRequestErrorMarshalForm(t *testing.T) {
 	client := &mockClient{}
 
@@ -759,6 +2015,12 @@ func TestRequestErrorReadFile(t *testing.T) {
 	resp.chain.assertFailed(t)
 
 	assert.True(t, resp.Raw() == nil)
+
+	req2 := NewRequest(config, "METHOD", "url")
+
+	req2.WithMultipart()
+	req2.WithFile("a", "/no/such/file")
+	req2.chain.assertFailed(t)
 }
 
 func TestRequestErrorSend(t *testing.T) {
@@ -829,6 +2091,16 @@ func TestRequestErrorConflictBody(t *testing.T) {
 	req7.WithBody(nil)
 	req7.WithMultipart()
 	req7.chain.assertFailed(t)
+
+	req8 := NewRequest(config, "METHOD", "url")
+	req8.WithBytes([]byte("a"))
+	req8.WithText("b")
+	req8.chain.assertFailed(t)
+
+	req9 := NewRequest(config, "METHOD", "url")
+	req9.WithText("a")
+	req9.WithBytes([]byte("b"))
+	req9.chain.assertFailed(t)
 }
 
 func TestRequestErrorConflictType(t *testing.T) {
@@ -855,13 +2127,28 @@ func TestRequestErrorConflictType(t *testing.T) {
 
 	req3 := NewRequest(config, "METHOD", "url")
 	req3.WithText("")
-	req3.WithField("a", "b")
+	req3.WithFormField("a", "b")
 	req3.chain.assertFailed(t)
 
 	req4 := NewRequest(config, "METHOD", "url")
 	req4.WithText("")
 	req4.WithMultipart()
 	req4.chain.assertFailed(t)
+
+	req5 := NewRequest(config, "METHOD", "url")
+	req5.WithJSON(map[string]interface{}{"a": "b"})
+	req5.WithForm(map[string]interface{}{"a": "b"})
+	req5.chain.assertFailed(t)
+
+	req6 := NewRequest(config, "METHOD", "url")
+	req6.WithForm(map[string]interface{}{"a": "b"})
+	req6.WithJSON(map[string]interface{}{"a": "b"})
+	req6.chain.assertFailed(t)
+
+	req7 := NewRequest(config, "METHOD", "url")
+	req7.WithFormField("a", "b")
+	req7.WithJSON(map[string]interface{}{"a": "b"})
+	req7.chain.assertFailed(t)
 }
 
 func TestRequestErrorConflictMultipart(t *testing.T) {
@@ -882,7 +2169,7 @@ func TestRequestErrorConflictMultipart(t *testing.T) {
 	req1.chain.assertFailed(t)
 
 	req2 := NewRequest(config, "METHOD", "url")
-	req2.WithField("a", "b")
+	req2.WithFormField("a", "b")
 	req2.WithMultipart()
 	req2.chain.assertFailed(t)
 
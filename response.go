@@ -1,12 +1,20 @@
 package httpexpect
 
 import (
+	"bufio"
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
-	"github.com/ajg/form"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"io"
 	"io/ioutil"
 	"mime"
 	"net/http"
+	"net/url"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -15,10 +23,12 @@ import (
 
 // Response provides methods to inspect attached http.Response object.
 type Response struct {
-	chain   chain
-	resp    *http.Response
-	content []byte
-	time    time.Duration
+	chain     chain
+	resp      *http.Response
+	content   *responseContent
+	time      time.Duration
+	timing    *timingTrace
+	websocket *websocket.Conn
 }
 
 // NewResponse returns a new Response given a reporter used to report failures
@@ -27,61 +37,343 @@ type Response struct {
 // Both reporter and response should not be nil. If response is nil, failure
 // is reported.
 //
-// If duration, it defines response time to be reported by response.Time().
+// If duration is given, it defines response time to be reported by
+// response.Duration().
+//
+// If response has "Content-Encoding" header set to "gzip" or "deflate",
+// response body is decompressed automatically. Headers are not modified,
+// so Header("Content-Encoding") still reports the original encoding.
 func NewResponse(
 	reporter Reporter, response *http.Response, duration ...time.Duration) *Response {
 	var dr time.Duration
 	if len(duration) > 0 {
 		dr = duration[0]
 	}
-	return makeResponse(makeChain(reporter), response, dr)
+	return makeResponse(makeChain(reporter), response, dr, true, 0, false)
 }
 
-func makeResponse(chain chain, response *http.Response, duration time.Duration) *Response {
+// makeResponse creates Response and reads its body; if stream is true,
+// body is instead read when it's first needed, see Request.WithStreaming
+func makeResponse(
+	chain chain, response *http.Response, duration time.Duration,
+	decompress bool, maxBodySize int64, stream bool) *Response {
 	if response == nil {
 		chain.fail("expected non-nil response")
 	}
-	content := getContent(&chain, response)
-	return &Response{
-		chain:   chain,
-		resp:    response,
-		content: content,
-		time:    duration,
+	r := &Response{
+		chain: chain,
+		resp:  response,
+		content: &responseContent{
+			decompress: decompress,
+			limit:      maxBodySize,
+		},
+		time: duration,
+	}
+	if !stream {
+		r.getContent()
 	}
+	return r
+}
+
+// responseContent holds response body, which is read on first use; it's
+// shared between copies of Response, e.g. made by Require
+type responseContent struct {
+	state      contentState
+	data       []byte
+	err        error
+	decompress bool
+	limit      int64
 }
 
-func getContent(chain *chain, resp *http.Response) []byte {
-	if chain.failed() {
+type contentState int
+
+const (
+	contentPending contentState = iota
+	contentRead
+	contentFailed
+	contentStreamed
+)
+
+// getContent returns response body, reading it if it's not read yet
+func (r *Response) getContent() []byte {
+	if r.chain.failed() {
+		return nil
+	}
+
+	c := r.content
+
+	if c.state == contentPending {
+		c.data, c.err = readContent(r.resp, c.decompress, c.limit)
+		if c.err != nil {
+			c.state = contentFailed
+		} else {
+			c.state = contentRead
+		}
+	}
+
+	switch c.state {
+	case contentFailed:
+		r.chain.fail(c.err.Error())
+		return nil
+
+	case contentStreamed:
+		r.chain.fail("\nexpected response body that is not consumed yet," +
+			" but it was already streamed by JSONStream")
 		return nil
 	}
 
+	return c.data
+}
+
+// readContent reads and decompresses response body; after that, response
+// Body is replaced with reader of buffered raw content
+func readContent(resp *http.Response, decompress bool, limit int64) ([]byte, error) {
 	if resp.Body == nil {
-		return []byte{}
+		return []byte{}, nil
 	}
 
 	content, err := ioutil.ReadAll(resp.Body)
+
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(content))
+
 	if err != nil {
-		chain.fail(err.Error())
-		return nil
+		return nil, err
+	}
+
+	if decompress {
+		return decompressContent(resp, content, limit)
+	}
+
+	return content, nil
+}
+
+// openContent is like readContent, but returns reader that decompresses
+// response body on the fly, without buffering it
+func openContent(resp *http.Response, decompress bool, limit int64) (
+	io.ReadCloser, error) {
+	if resp.Body == nil {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	if !decompress {
+		return resp.Body, nil
+	}
+
+	// empty body is not decompressed, like in decompressContent
+	buffered := bufio.NewReader(resp.Body)
+	if _, err := buffered.Peek(1); err == io.EOF {
+		return resp.Body, nil
+	}
+
+	encoding := contentEncoding(resp)
+
+	reader, err := newDecompressor(encoding, buffered)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("\ncan't decompress %q response body:\n  %s",
+			encoding, err.Error())
+	}
+
+	if reader == nil {
+		return bodyReader{buffered, resp.Body}, nil
 	}
 
-	return content
+	var body io.ReadCloser = bodyReader{reader, resp.Body}
+	if limit > 0 {
+		body = newLimitedBody(body, limit)
+	}
+
+	return body, nil
+}
+
+// bodyReader reads from wrapped reader and closes original body
+type bodyReader struct {
+	io.Reader
+	io.Closer
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	limit  int64
+	remain int64
+}
+
+func newLimitedBody(body io.ReadCloser, limit int64) *limitedBody {
+	return &limitedBody{body, limit, limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remain < 0 {
+		return 0, b.exceeded()
+	}
+
+	// read one extra byte to detect that the limit is exceeded
+	if int64(len(p)) > b.remain+1 {
+		p = p[:b.remain+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remain -= int64(n)
+
+	if b.remain < 0 {
+		return n + int(b.remain), b.exceeded()
+	}
+
+	return n, err
+}
+
+func (b *limitedBody) exceeded() error {
+	return fmt.Errorf(
+		"\nexpected response body size:\n  <= %d bytes\n\nbut got:\n  larger body",
+		b.limit)
+}
+
+func decompressContent(
+	resp *http.Response, content []byte, limit int64) ([]byte, error) {
+	if len(content) == 0 {
+		return content, nil
+	}
+
+	encoding := contentEncoding(resp)
+
+	reader, err := newDecompressor(encoding, bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("\ncan't decompress %q response body:\n  %s",
+			encoding, err.Error())
+	}
+
+	if reader == nil {
+		return content, nil
+	}
+
+	defer reader.Close()
+
+	// compressed body may be much smaller than decompressed one,
+	// so the limit is applied to both
+	var limited *limitedBody
+	if limit > 0 {
+		limited = newLimitedBody(reader, limit)
+		reader = limited
+	}
+
+	decompressed, err := ioutil.ReadAll(reader)
+	if limited != nil && limited.remain < 0 {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("\ncan't decompress %q response body:\n  %s",
+			encoding, err.Error())
+	}
+
+	return decompressed, nil
+}
+
+func contentEncoding(resp *http.Response) string {
+	return strings.ToLower(
+		strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+}
+
+// newDecompressor returns reader that decompresses body encoded with
+// given Content-Encoding, or nil if encoding is not supported
+func newDecompressor(encoding string, body io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+
+	case "deflate":
+		// RFC 2616 defines "deflate" as zlib format, but some servers
+		// send raw deflate stream, so fallback to it
+		buffered := bufio.NewReader(body)
+		if header, _ := buffered.Peek(2); isZlibHeader(header) {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// isZlibHeader reports whether b starts with zlib header (RFC 1950)
+// accepted by zlib.NewReader: deflate method, valid checksum, and no
+// preset dictionary
+func isZlibHeader(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	return b[0]&0x0f == 8 && b[1]&0x20 == 0 &&
+		(uint(b[0])<<8|uint(b[1]))%31 == 0
 }
 
 // Raw returns underlying http.Response object.
 // This is the value originally passed to NewResponse.
+//
+// Response body is read when Response is created, or, if the request was
+// sent with WithStreaming, when it's first needed. After that, Body field
+// of returned object is replaced with a reader of buffered body contents,
+// so it may be read again, once. Note that the buffer holds body as it
+// was received, i.e. before decompression.
 func (r *Response) Raw() *http.Response {
 	return r.resp
 }
 
-// Time returns a new Number object that may be used to inspect response time,
-// in nanoseconds.
+// Require returns a copy of Response object that reports failures as fatal,
+// so that e.g. a failed status check aborts the test, while other checks
+// remain non-fatal. Objects obtained from the copy (like JSON or Header)
+// report failures as fatal too.
+//
+// See Value.Require for details.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Require().Status(http.StatusOK)
+func (r *Response) Require() *Response {
+	ret := *r
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Duration returns a new Number object that may be used to inspect response
+// round-trip time, in nanoseconds.
+//
+// For responses returned by Request.Expect(), this is the time elapsed
+// between sending the request and receiving the response headers, the same
+// value that is passed to Printer.Response().
+//
+// Example:
+//  resp := NewResponse(t, response, time.Duration(10000000))
+//  resp.Duration().Equal(10 * time.Millisecond)
+//  resp.Duration().Lt(float64(time.Second))
+func (r *Response) Duration() *Number {
+	return &Number{r.chain.enter(".Duration"), float64(r.time), 0}
+}
+
+// Timing returns a new Timing object that may be used to inspect durations
+// of request round-trip phases.
+//
+// Timing is available only for responses returned by Request.Expect(), when
+// Config.EnableTiming is set. Otherwise, failure is reported.
+//
+// Example:
+//  resp := req.Expect()
+//  resp.Timing().TimeToFirstByte().Lt(float64(time.Millisecond * 100))
+func (r *Response) Timing() *Timing {
+	if r.timing == nil {
+		r.chain.fail("\nexpected response with timing information," +
+			" but Config.EnableTiming is not set")
+		return &Timing{chain: r.chain.enter(".Timing")}
+	}
+	return r.timing.makeTiming(r.chain.enter(".Timing"))
+}
+
+// Time is an alias for Duration.
 //
 // Example:
 //  resp := NewResponse(t, response, time.Duration(10000000))
 //  resp.Time().Equal(10 * time.Millisecond)
 func (r *Response) Time() *Number {
-	return &Number{r.chain, float64(r.time)}
+	return r.Duration()
 }
 
 // Status succeedes if response contains given status code.
@@ -97,6 +389,89 @@ func (r *Response) Status(status int) *Response {
 	return r
 }
 
+// NotStatus succeedes if response status code differs from given one.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.NotStatus(http.StatusInternalServerError)
+func (r *Response) NotStatus(status int) *Response {
+	if r.chain.failed() {
+		return r
+	}
+	if r.resp.StatusCode == status {
+		r.chain.fail("\nexpected status != %s, but got %s",
+			statusText(status), statusText(r.resp.StatusCode))
+	}
+	return r
+}
+
+// StatusRange is enum for response status ranges.
+type StatusRange int
+
+const (
+	// Status1xx defines "Informational" status codes.
+	Status1xx StatusRange = 100
+
+	// Status2xx defines "Success" status codes.
+	Status2xx StatusRange = 200
+
+	// Status3xx defines "Redirection" status codes.
+	Status3xx StatusRange = 300
+
+	// Status4xx defines "Client Error" status codes.
+	Status4xx StatusRange = 400
+
+	// Status5xx defines "Server Error" status codes.
+	Status5xx StatusRange = 500
+)
+
+// StatusRange succeedes if response status belongs to given range.
+//
+// Supported ranges:
+//  - Status1xx - Informational
+//  - Status2xx - Success
+//  - Status3xx - Redirection
+//  - Status4xx - Client Error
+//  - Status5xx - Server Error
+//
+// See https://en.wikipedia.org/wiki/List_of_HTTP_status_codes.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.StatusRange(Status2xx)
+func (r *Response) StatusRange(rn StatusRange) *Response {
+	if r.chain.failed() {
+		return r
+	}
+
+	expected := statusRangeText(int(rn))
+	actual := statusRangeText(r.resp.StatusCode)
+
+	if actual == "" || actual != expected {
+		r.chain.fail("\nexpected status from range:\n  %q\n\nbut got:\n  %q (%q)",
+			expected, actual, statusText(r.resp.StatusCode))
+	}
+
+	return r
+}
+
+func statusRangeText(code int) string {
+	switch {
+	case code >= 100 && code < 200:
+		return "1xx Informational"
+	case code >= 200 && code < 300:
+		return "2xx Success"
+	case code >= 300 && code < 400:
+		return "3xx Redirection"
+	case code >= 400 && code < 500:
+		return "4xx Client Error"
+	case code >= 500 && code < 600:
+		return "5xx Server Error"
+	default:
+		return ""
+	}
+}
+
 func statusText(code int) string {
 	if s := http.StatusText(code); s != "" {
 		return strconv.Itoa(code) + " " + s
@@ -104,43 +479,130 @@ func statusText(code int) string {
 	return strconv.Itoa(code)
 }
 
-// Header returns a new Object that may be used to inspect header map.
+// Headers returns a new Object that may be used to inspect header map.
+//
+// Every header is represented as an array of its values (even if it has
+// only one value).
 //
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Headers().Value("Content-Type").Contains("application-json")
+//  resp.Headers().Value("Content-Type").Array().Elements("application/json")
 func (r *Response) Headers() *Object {
 	var value map[string]interface{}
 	if !r.chain.failed() {
 		value, _ = canonMap(&r.chain, r.resp.Header)
 	}
-	return &Object{r.chain, value}
+	return &Object{r.chain.enter(".Headers"), value}
 }
 
 // Header returns a new String object that may be used to inspect given header.
 //
+// Header name is case-insensitive. If header has multiple values, only the
+// first one is used. If there is no such header, empty string is used.
+//
 // Example:
 //  resp := NewResponse(t, response)
-//  resp.Header("Content-Type").Contains("application-json")
+//  resp.Header("Content-Type").Contains("application/json")
 func (r *Response) Header(header string) *String {
 	value := ""
 	if !r.chain.failed() {
 		value = r.resp.Header.Get(header)
 	}
-	return &String{r.chain, value}
+	return &String{r.chain.enter(".Header[%q]", header), value}
+}
+
+// Cookies returns a new Array object with all cookie names set by this response.
+// Returned Array contains a String value for every cookie name.
+//
+// Note that this returns only cookies set by Set-Cookie headers of this response.
+// It doesn't return session cookies from previous responses, which may be stored
+// in a cookie jar.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Cookies().Contains("session")
+func (r *Response) Cookies() *Array {
+	if r.chain.failed() {
+		return &Array{r.chain.enter(".Cookies"), nil}
+	}
+	names := []interface{}{}
+	for _, c := range r.resp.Cookies() {
+		names = append(names, c.Name)
+	}
+	return &Array{r.chain.enter(".Cookies"), names}
+}
+
+// Cookie returns a new Cookie object that may be used to inspect given cookie
+// set by this response.
+//
+// Note that this returns only cookies set by Set-Cookie headers of this response.
+// It doesn't return session cookies from previous responses, which may be stored
+// in a cookie jar.
+//
+// If there is no cookie with given name, failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Cookie("session").Domain().Equal("example.com")
+func (r *Response) Cookie(name string) *Cookie {
+	if r.chain.failed() {
+		return &Cookie{r.chain.enter(".Cookie[%q]", name), nil}
+	}
+	names := []string{}
+	for _, c := range r.resp.Cookies() {
+		if c.Name == name {
+			return &Cookie{r.chain.enter(".Cookie[%q]", name), c}
+		}
+		names = append(names, c.Name)
+	}
+	r.chain.fail("\nexpected response with cookie:\n  %q\n\nbut got only cookies:\n%s",
+		name, dumpValue(names))
+	return &Cookie{r.chain.enter(".Cookie[%q]", name), nil}
+}
+
+// Websocket returns a new Websocket object that may be used to interact
+// with WebSocket server.
+//
+// Websocket succeedes only if the request was sent with WithWebsocketUpgrade
+// and the server accepted the handshake. It's the caller's responsibility to
+// close the connection after use.
+//
+// Example:
+//  resp := req.WithWebsocketUpgrade().Expect()
+//  ws := resp.Status(http.StatusSwitchingProtocols).Websocket()
+//  defer ws.Close()
+//
+//  ws.WriteText("hello")
+//  ws.Expect().Text().Equal("hello")
+func (r *Response) Websocket() *Websocket {
+	if !r.chain.failed() && r.websocket == nil {
+		r.chain.fail("\nexpected WebSocket connection, " +
+			"but response was received without WebSocket upgrade")
+	}
+	return makeWebsocket(r.chain.enter(".Websocket"), r.websocket)
 }
 
 // Body returns a new String object that may be used to inspect response body.
 //
+// String.Length may be used to inspect body size in bytes.
+//
 // Example:
 //  resp := NewResponse(t, response)
 //  resp.Body().NotEmpty()
+//  resp.Body().Length().Le(1024)
 func (r *Response) Body() *String {
-	return &String{r.chain, string(r.content)}
+	content := r.getContent()
+	return &String{r.chain.enter(".Body"), string(content)}
 }
 
 // NoContent succeedes if response contains empty Content-Type header and
 // empty body.
+//
+// NoContent doesn't check status code; use Status to check it too.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.Status(http.StatusNoContent).NoContent()
 func (r *Response) NoContent() *Response {
 	if r.chain.failed() {
 		return r
@@ -149,7 +611,41 @@ func (r *Response) NoContent() *Response {
 	contentType := r.resp.Header.Get("Content-Type")
 
 	r.checkEqual("\"Content-Type\" header", "", contentType)
-	r.checkEqual("body", "", string(r.content))
+
+	content := r.getContent()
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkEqual("body", "", string(content))
+
+	return r
+}
+
+// ContentLength succeedes if response contains Content-Length header with
+// given value.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.ContentLength(1024)
+func (r *Response) ContentLength(length int64) *Response {
+	if r.chain.failed() {
+		return r
+	}
+
+	header := r.resp.Header.Get("Content-Length")
+	if header == "" {
+		r.chain.fail("\nexpected response with \"Content-Length\" header")
+		return r
+	}
+
+	actual, err := strconv.ParseInt(header, 10, 64)
+	if err != nil {
+		r.chain.fail("\nbad \"Content-Length\" header:\n  %q", header)
+		return r
+	}
+
+	r.checkEqual("\"Content-Length\" header", length, actual)
 
 	return r
 }
@@ -162,6 +658,16 @@ func (r *Response) NoContent() *Response {
 //
 // If charset is omitted, and mediaType is also empty, Content-Type header
 // should contain no charset.
+//
+// Header is parsed using mime.ParseMediaType. Both media type and charset
+// are compared case-insensitively.
+//
+// If mediaType has "type/*" form, any subtype of given type is accepted.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.ContentType("application/json", "utf-8")
+//  resp.ContentType("text/*")
 func (r *Response) ContentType(mediaType string, charset ...string) *Response {
 	r.checkContentType(mediaType, charset...)
 	return r
@@ -169,8 +675,8 @@ func (r *Response) ContentType(mediaType string, charset ...string) *Response {
 
 // Text returns a new String object that may be used to inspect response body.
 //
-// Text succeedes if response contains "text/plain" Content-Type header
-// with empty or "utf-8" charset.
+// Text succeedes if response contains "text/*" Content-Type header
+// (e.g. "text/plain" or "text/html") with empty or "utf-8" charset.
 //
 // Example:
 //  resp := NewResponse(t, response)
@@ -178,11 +684,11 @@ func (r *Response) ContentType(mediaType string, charset ...string) *Response {
 func (r *Response) Text() *String {
 	var content string
 
-	if !r.chain.failed() && r.checkContentType("text/plain") {
-		content = string(r.content)
+	if !r.chain.failed() && r.checkContentType("text/*") {
+		content = string(r.getContent())
 	}
 
-	return &String{r.chain, content}
+	return &String{r.chain.enter(".Text"), content}
 }
 
 // Form returns a new Object that may be used to inspect form contents
@@ -190,14 +696,18 @@ func (r *Response) Text() *String {
 //
 // Form succeedes if response contains "application/x-www-form-urlencoded"
 // Content-Type header and if form may be decoded from response body.
-// Decoding is performed using https://github.com/ajg/form.
+// Decoding is performed using url.ParseQuery.
+//
+// Keys with a single value are represented as strings, and keys with
+// multiple values are represented as arrays of strings.
 //
 // Example:
 //  resp := NewResponse(t, response)
 //  resp.Form().Value("foo").Equal("bar")
+//  resp.Form().Value("tags").Array().Elements("a", "b")
 func (r *Response) Form() *Object {
 	object := r.getForm()
-	return &Object{r.chain, object}
+	return &Object{r.chain.enter(".Form"), object}
 }
 
 func (r *Response) getForm() map[string]interface{} {
@@ -209,14 +719,30 @@ func (r *Response) getForm() map[string]interface{} {
 		return nil
 	}
 
-	decoder := form.NewDecoder(bytes.NewReader(r.content))
+	content := r.getContent()
+	if r.chain.failed() {
+		return nil
+	}
 
-	var object map[string]interface{}
-	if err := decoder.Decode(&object); err != nil {
+	values, err := url.ParseQuery(string(content))
+	if err != nil {
 		r.chain.fail(err.Error())
 		return nil
 	}
 
+	object := map[string]interface{}{}
+	for k, v := range values {
+		if len(v) == 1 {
+			object[k] = v[0]
+		} else {
+			array := make([]interface{}, len(v))
+			for i := range v {
+				array[i] = v[i]
+			}
+			object[k] = array
+		}
+	}
+
 	return object
 }
 
@@ -231,7 +757,7 @@ func (r *Response) getForm() map[string]interface{} {
 //  resp.JSON().Array().Elements("foo", "bar")
 func (r *Response) JSON() *Value {
 	value := r.getJSON()
-	return &Value{r.chain, value}
+	return &Value{r.chain.enter(".JSON"), value}
 }
 
 func (r *Response) getJSON() interface{} {
@@ -243,8 +769,227 @@ func (r *Response) getJSON() interface{} {
 		return nil
 	}
 
+	content := r.getContent()
+	if r.chain.failed() {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		r.chain.fail(err.Error())
+		return nil
+	}
+
+	return value
+}
+
+// JSONStream returns a new ArrayStream object that may be used to inspect
+// elements of JSON array in response, decoding them one by one.
+//
+// Unlike JSON().Array(), it doesn't build decoded representation of the
+// whole array; every element is decoded when it's visited and discarded
+// afterwards.
+//
+// By default, response body is read and buffered in memory entirely when
+// Response is created, and JSONStream decodes elements from the buffer.
+// If the request was sent with WithStreaming, and body was not read yet
+// by other methods, elements are decoded directly from the connection
+// while body is received, and body is not buffered. In this case, the
+// array may be iterated only once, and methods that need the whole body,
+// like Body or JSON, report failure afterwards.
+//
+// JSONStream succeedes if response contains "application/json" Content-Type
+// header with empty or "utf-8" charset.
+//
+// Example:
+//  resp := req.WithStreaming().Expect()
+//  resp.JSONStream().ForEach(func(index int, value *httpexpect.Value) {
+//      value.Object().ContainsKey("id")
+//  })
+func (r *Response) JSONStream() *ArrayStream {
+	if r.chain.failed() || !r.checkContentType("application/json") {
+		return &ArrayStream{chain: r.chain.enter(".JSONStream")}
+	}
+
+	c := r.content
+
+	if c.state == contentPending {
+		body, err := openContent(r.resp, c.decompress, c.limit)
+		if err != nil {
+			c.state, c.err = contentFailed, err
+			r.chain.fail(err.Error())
+			return &ArrayStream{chain: r.chain.enter(".JSONStream")}
+		}
+		c.state = contentStreamed
+		return &ArrayStream{chain: r.chain.enter(".JSONStream"), body: body}
+	}
+
+	content := r.getContent()
+	return &ArrayStream{chain: r.chain.enter(".JSONStream"), content: content}
+}
+
+// MatchGolden succeedes if response body matches contents of golden file
+// at given path.
+//
+// If response has "application/json" Content-Type, or another JSON media
+// type with "+json" suffix (e.g. "application/problem+json"), body and file
+// are compared as JSON values, so key order and whitespace are ignored.
+// Otherwise, they are compared byte-by-byte.
+//
+// If UPDATE_GOLDEN environment variable is set to non-empty value, golden
+// file is (re)written from response body instead, and no comparison is
+// performed. JSON bodies are written indented.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.MatchGolden("testdata/users.golden.json")
+func (r *Response) MatchGolden(path string) *Response {
+	if r.chain.failed() {
+		return r
+	}
+
+	isJSON := false
+	if mediaType, _, err := mime.ParseMediaType(
+		r.resp.Header.Get("Content-Type")); err == nil {
+		isJSON = mediaType == "application/json" ||
+			strings.HasSuffix(mediaType, "+json")
+	}
+
+	content := r.getContent()
+	if r.chain.failed() {
+		return r
+	}
+
+	if updateGolden() {
+		r.writeGolden(path, content, isJSON)
+		return r
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		r.chain.fail("\ncan't read golden file (set UPDATE_GOLDEN=1 to create it):\n  %s",
+			err.Error())
+		return r
+	}
+
+	if isJSON {
+		expected, ok := parseJSON(&r.chain, string(golden))
+		if !ok {
+			return r
+		}
+		var actual interface{}
+		if err := json.Unmarshal(content, &actual); err != nil {
+			r.chain.fail(err.Error())
+			return r
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			r.chain.fail(
+				"\nexpected body matching golden file %q:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+				path, dumpValue(expected), dumpValue(actual),
+				diffValues(expected, actual))
+		}
+		return r
+	}
+
+	if !bytes.Equal(golden, content) {
+		r.chain.fail(
+			"\nexpected body matching golden file %q\n\ndiff:\n%s",
+			path, diffLines(string(golden), string(content)))
+	}
+
+	return r
+}
+
+// updateGolden reports whether MatchGolden should rewrite golden files;
+// it's a variable so that tests can override it
+var updateGolden = func() bool {
+	return os.Getenv("UPDATE_GOLDEN") != ""
+}
+
+func (r *Response) writeGolden(path string, content []byte, isJSON bool) {
+	data := content
+
+	if isJSON {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, content, "", "  "); err != nil {
+			r.chain.fail(err.Error())
+			return
+		}
+		buf.WriteString("\n")
+		data = buf.Bytes()
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		r.chain.fail("\ncan't write golden file:\n  %s", err.Error())
+	}
+}
+
+// JSONP returns a new Value object that may be used to inspect JSONP contents
+// of response.
+//
+// JSONP succeedes if response contains "application/javascript" or
+// "text/javascript" Content-Type header with empty or "utf-8" charset and
+// response body of the following form:
+//  callback(<valid json>);
+// or:
+//  callback(<valid json>)
+//
+// Whitespaces are allowed. Callback name should be non-empty.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.JSONP("myCallback").Array().Elements("foo", "bar")
+func (r *Response) JSONP(callback string) *Value {
+	value := r.getJSONP(callback)
+	return &Value{r.chain.enter(".JSONP"), value}
+}
+
+func (r *Response) getJSONP(callback string) interface{} {
+	if r.chain.failed() {
+		return nil
+	}
+
+	if callback == "" {
+		r.chain.fail("\nunexpected empty JSONP callback name")
+		return nil
+	}
+
+	mediaType := "application/javascript"
+	if t, _, err := mime.ParseMediaType(
+		r.resp.Header.Get("Content-Type")); err == nil && t == "text/javascript" {
+		mediaType = t
+	}
+
+	if !r.checkContentType(mediaType) {
+		return nil
+	}
+
+	body := r.getContent()
+	if r.chain.failed() {
+		return nil
+	}
+
+	content := strings.TrimSpace(string(body))
+	content = strings.TrimSpace(strings.TrimSuffix(content, ";"))
+
+	if !strings.HasPrefix(content, callback) {
+		r.chain.fail("\nexpected JSONP body with callback %s, but got:\n  %s",
+			strconv.Quote(callback), strconv.Quote(string(body)))
+		return nil
+	}
+
+	content = strings.TrimSpace(content[len(callback):])
+
+	if !strings.HasPrefix(content, "(") || !strings.HasSuffix(content, ")") {
+		r.chain.fail("\nexpected JSONP body in form %s, but got:\n  %s",
+			strconv.Quote(callback+"(...)"), strconv.Quote(string(body)))
+		return nil
+	}
+
+	content = content[1 : len(content)-1]
+
 	var value interface{}
-	if err := json.Unmarshal(r.content, &value); err != nil {
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
 		r.chain.fail(err.Error())
 		return nil
 	}
@@ -272,11 +1017,11 @@ func (r *Response) checkContentType(expectedType string, expectedCharset ...stri
 		return false
 	}
 
-	if mediaType != expectedType {
+	if !matchMediaType(mediaType, expectedType) {
 		r.chain.fail(
 			"\nexpected \"Content-Type\" header with %s media type,"+
-				"\nbut got %s",
-			strconv.Quote(expectedType), strconv.Quote(mediaType))
+				"\nbut got %s with params:\n%s",
+			strconv.Quote(expectedType), strconv.Quote(mediaType), dumpValue(params))
 		return false
 	}
 
@@ -286,16 +1031,17 @@ func (r *Response) checkContentType(expectedType string, expectedCharset ...stri
 		if charset != "" && !strings.EqualFold(charset, "utf-8") {
 			r.chain.fail(
 				"\nexpected \"Content-Type\" header with \"utf-8\" or empty charset,"+
-					"\nbut got %s",
-				strconv.Quote(charset))
+					"\nbut got %s charset in %s media type with params:\n%s",
+				strconv.Quote(charset), strconv.Quote(mediaType), dumpValue(params))
 			return false
 		}
 	} else {
 		if !strings.EqualFold(charset, expectedCharset[0]) {
 			r.chain.fail(
 				"\nexpected \"Content-Type\" header with %s charset,"+
-					"\nbut got %s",
-				strconv.Quote(expectedCharset[0]), strconv.Quote(charset))
+					"\nbut got %s charset in %s media type with params:\n%s",
+				strconv.Quote(expectedCharset[0]), strconv.Quote(charset),
+				strconv.Quote(mediaType), dumpValue(params))
 			return false
 		}
 	}
@@ -303,6 +1049,15 @@ func (r *Response) checkContentType(expectedType string, expectedCharset ...stri
 	return true
 }
 
+func matchMediaType(mediaType, expectedType string) bool {
+	if strings.HasSuffix(expectedType, "/*") {
+		prefix := expectedType[:len(expectedType)-1]
+		return len(mediaType) > len(prefix) &&
+			strings.EqualFold(mediaType[:len(prefix)], prefix)
+	}
+	return strings.EqualFold(mediaType, expectedType)
+}
+
 func (r *Response) checkEqual(what string, expected, actual interface{}) {
 	if !reflect.DeepEqual(expected, actual) {
 		r.chain.fail("\nexpected %s equal to:\n%s\n\nbut got:\n%s", what,
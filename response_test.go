@@ -2,9 +2,14 @@ package httpexpect
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -14,27 +19,104 @@ func TestResponseFailed(t *testing.T) {
 
 	chain.fail("fail")
 
-	resp := &Response{chain, nil, nil, 0}
+	resp := &Response{chain, nil, nil, 0, nil, nil}
 
 	resp.chain.assertFailed(t)
 
 	assert.False(t, resp.Time() == nil)
+	assert.False(t, resp.Duration() == nil)
 	assert.False(t, resp.Headers() == nil)
 	assert.False(t, resp.Header("foo") == nil)
+	assert.False(t, resp.Cookies() == nil)
+	assert.False(t, resp.Cookie("foo") == nil)
 	assert.False(t, resp.Body() == nil)
 	assert.False(t, resp.JSON() == nil)
 
 	resp.Headers().chain.assertFailed(t)
 	resp.Header("foo").chain.assertFailed(t)
+	resp.Cookies().chain.assertFailed(t)
+	resp.Cookie("foo").chain.assertFailed(t)
 	resp.Body().chain.assertFailed(t)
 	resp.Text().chain.assertFailed(t)
 	resp.JSON().chain.assertFailed(t)
+	resp.JSONP("").chain.assertFailed(t)
+	resp.JSONStream().chain.assertFailed(t)
+	resp.Websocket().chain.assertFailed(t)
+	resp.Timing().chain.assertFailed(t)
 
 	resp.Status(123)
+	resp.NotStatus(123)
+	resp.ContentLength(123)
+	resp.MatchGolden("")
+	resp.StatusRange(Status2xx)
 	resp.NoContent()
 	resp.ContentType("", "")
 }
 
+func TestResponseNotStatus(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	resp := NewResponse(reporter, &http.Response{
+		StatusCode: http.StatusOK,
+	})
+
+	resp.NotStatus(http.StatusInternalServerError)
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.NotStatus(http.StatusOK)
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
+func TestResponseStatusRange(t *testing.T) {
+	ranges := []StatusRange{
+		Status1xx,
+		Status2xx,
+		Status3xx,
+		Status4xx,
+		Status5xx,
+	}
+
+	cases := []struct {
+		status int
+		match  StatusRange
+	}{
+		{99, -1},
+		{100, Status1xx},
+		{199, Status1xx},
+		{200, Status2xx},
+		{204, Status2xx},
+		{299, Status2xx},
+		{300, Status3xx},
+		{302, Status3xx},
+		{400, Status4xx},
+		{404, Status4xx},
+		{500, Status5xx},
+		{503, Status5xx},
+		{599, Status5xx},
+		{600, -1},
+	}
+
+	for _, tc := range cases {
+		for _, rn := range ranges {
+			reporter := newMockReporter(t)
+
+			resp := NewResponse(reporter, &http.Response{
+				StatusCode: tc.status,
+			})
+
+			resp.StatusRange(rn)
+
+			if rn == tc.match {
+				resp.chain.assertOK(t)
+			} else {
+				resp.chain.assertFailed(t)
+			}
+		}
+	}
+}
+
 func TestResponseTime(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -50,6 +132,16 @@ func TestResponseTime(t *testing.T) {
 
 	rt.Equal(10 * time.Millisecond)
 	rt.chain.assertOK(t)
+
+	rd := resp.Duration()
+
+	assert.Equal(t, float64(duration), rd.Raw())
+
+	rd.Lt(float64(time.Second))
+	rd.chain.assertOK(t)
+
+	rd.Gt(float64(time.Second))
+	rd.chain.assertFailed(t)
 }
 
 func TestResponseHeaders(t *testing.T) {
@@ -89,6 +181,104 @@ func TestResponseHeaders(t *testing.T) {
 	resp.Header("Bad-Header").Empty().chain.assertOK(t)
 }
 
+func TestResponseHeadersMultiple(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"First-Header": {"foo", "bar"},
+	}
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       nil,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.Header("First-Header").Equal("foo").chain.assertOK(t)
+	resp.Header("first-header").Equal("foo").chain.assertOK(t)
+
+	resp.Headers().Value("First-Header").Array().Elements("foo", "bar").
+		chain.assertOK(t)
+}
+
+func TestResponseCookies(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Set-Cookie": {
+			"foo=aaa",
+			"bar=bbb; expires=Fri, 31 Dec 2010 23:59:59 GMT; " +
+				"path=/xxx; domain=example.com; max-age=60; secure; httponly",
+		},
+	}
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       nil,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	assert.Equal(t, []interface{}{"foo", "bar"}, resp.Cookies().Raw())
+	resp.chain.assertOK(t)
+
+	c1 := resp.Cookie("foo")
+	resp.chain.assertOK(t)
+	assert.Equal(t, "foo", c1.Raw().Name)
+	assert.Equal(t, "aaa", c1.Raw().Value)
+	assert.Equal(t, "", c1.Raw().Domain)
+	assert.Equal(t, "", c1.Raw().Path)
+
+	c2 := resp.Cookie("bar")
+	resp.chain.assertOK(t)
+	assert.Equal(t, "bar", c2.Raw().Name)
+	assert.Equal(t, "bbb", c2.Raw().Value)
+	assert.Equal(t, "example.com", c2.Raw().Domain)
+	assert.Equal(t, "/xxx", c2.Raw().Path)
+	assert.True(t, time.Date(2010, 12, 31, 23, 59, 59, 0, time.UTC).
+		Equal(c2.Raw().Expires))
+	assert.True(t, c2.Raw().Secure)
+	assert.True(t, c2.Raw().HttpOnly)
+
+	c2.Value().Equal("bbb")
+	c2.Path().Equal("/xxx")
+	c2.Domain().Equal("example.com")
+	c2.MaxAge().Equal(60)
+	c2.Expires().Equal(time.Date(2010, 12, 31, 23, 59, 59, 0, time.UTC))
+	c2.Secure().True()
+	c2.HttpOnly().True()
+	c2.chain.assertOK(t)
+
+	c3 := resp.Cookie("baz")
+	resp.chain.assertFailed(t)
+	c3.chain.assertFailed(t)
+	assert.True(t, c3.Raw() == nil)
+}
+
+func TestResponseNoCookies(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	resp := NewResponse(reporter, &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     nil,
+		Body:       nil,
+	})
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	assert.Equal(t, []interface{}{}, resp.Cookies().Raw())
+	resp.chain.assertOK(t)
+
+	c := resp.Cookie("foo")
+	resp.chain.assertFailed(t)
+	assert.True(t, c.Raw() == nil)
+}
+
 func TestResponseBody(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -102,6 +292,35 @@ func TestResponseBody(t *testing.T) {
 	assert.Equal(t, "body", resp.Body().Raw())
 	resp.chain.assertOK(t)
 	resp.chain.reset()
+
+	resp.Body().Length().Equal(4)
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+}
+
+func TestResponseRawBody(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	body := &mockBody{Reader: bytes.NewBufferString("body")}
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	assert.True(t, body.closed)
+
+	assert.Equal(t, "body", resp.Body().Raw())
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, httpResp, resp.Raw())
+
+	b, err := ioutil.ReadAll(resp.Raw().Body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "body", string(b))
 }
 
 func TestResponseNoContentEmpty(t *testing.T) {
@@ -140,6 +359,41 @@ func TestResponseNoContentEmpty(t *testing.T) {
 	resp.chain.reset()
 }
 
+func TestResponseContentLength(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := []struct {
+		header string
+		ok     bool
+	}{
+		{"4", true},
+		{"5", false},
+		{"", false},
+		{"bad", false},
+	}
+
+	for _, tc := range cases {
+		header := http.Header{}
+		if tc.header != "" {
+			header.Set("Content-Length", tc.header)
+		}
+
+		resp := NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("body")),
+		})
+
+		resp.ContentLength(4)
+
+		if tc.ok {
+			resp.chain.assertOK(t)
+		} else {
+			resp.chain.assertFailed(t)
+		}
+	}
+}
+
 func TestResponseNoContentNil(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -199,6 +453,10 @@ func TestResponseContentType(t *testing.T) {
 	resp.chain.assertOK(t)
 	resp.chain.reset()
 
+	resp.ContentType("Text/Plain", "UTF-8")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
 	resp.ContentType("bad")
 	resp.chain.assertFailed(t)
 	resp.chain.reset()
@@ -216,6 +474,30 @@ func TestResponseContentType(t *testing.T) {
 	resp.chain.reset()
 }
 
+func TestResponseContentTypeParams(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Content-Type": {"Application/JSON; Charset=UTF-8; foo=bar"},
+	}
+
+	resp := NewResponse(reporter, &http.Response{
+		Header: http.Header(headers),
+	})
+
+	resp.ContentType("application/json")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.ContentType("application/json", "utf-8")
+	resp.chain.assertOK(t)
+	resp.chain.reset()
+
+	resp.ContentType("application/json", "ascii")
+	resp.chain.assertFailed(t)
+	resp.chain.reset()
+}
+
 func TestResponseContentTypeEmptyCharset(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -276,6 +558,77 @@ func TestResponseContentTypeInvalid(t *testing.T) {
 	resp2.chain.reset()
 }
 
+func TestResponseDecompress(t *testing.T) {
+	body := `{"key": "value"}`
+
+	var gzipBody, zlibBody, flateBody bytes.Buffer
+
+	gw := gzip.NewWriter(&gzipBody)
+	gw.Write([]byte(body))
+	gw.Close()
+
+	zw := zlib.NewWriter(&zlibBody)
+	zw.Write([]byte(body))
+	zw.Close()
+
+	fw, _ := flate.NewWriter(&flateBody, flate.DefaultCompression)
+	fw.Write([]byte(body))
+	fw.Close()
+
+	cases := []struct {
+		encoding string
+		content  []byte
+	}{
+		{"", []byte(body)},
+		{"identity", []byte(body)},
+		{"gzip", gzipBody.Bytes()},
+		{"GZIP", gzipBody.Bytes()},
+		{"deflate", zlibBody.Bytes()},
+		{"deflate", flateBody.Bytes()},
+	}
+
+	for _, tc := range cases {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type":     {"application/json"},
+				"Content-Encoding": {tc.encoding},
+			},
+			Body: ioutil.NopCloser(bytes.NewReader(tc.content)),
+		}
+
+		resp := NewResponse(reporter, httpResp)
+		resp.chain.assertOK(t)
+
+		resp.Body().Equal(body)
+		resp.JSON().Object().ValueEqual("key", "value")
+		resp.Header("Content-Encoding").Equal(tc.encoding)
+
+		resp.chain.assertOK(t)
+	}
+}
+
+func TestResponseDecompressBadBody(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Encoding": {encoding},
+			},
+			Body: ioutil.NopCloser(bytes.NewBufferString("not compressed")),
+		}
+
+		resp := NewResponse(reporter, httpResp)
+		resp.chain.assertFailed(t)
+
+		assert.True(t, resp.content.data == nil)
+	}
+}
+
 func TestResponseText(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -328,6 +681,54 @@ func TestResponseText(t *testing.T) {
 	assert.Equal(t, "hello, world!", resp.Text().Raw())
 }
 
+func TestResponseTextSubtypes(t *testing.T) {
+	for _, ct := range []string{
+		"text/html",
+		"text/csv; charset=utf-8",
+		"TEXT/Plain",
+	} {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {ct}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString("hello")),
+		}
+
+		resp := NewResponse(reporter, httpResp)
+
+		resp.ContentType("text/*")
+		resp.chain.assertOK(t)
+		resp.chain.reset()
+
+		resp.ContentType("application/*")
+		resp.chain.assertFailed(t)
+		resp.chain.reset()
+
+		resp.Text().Equal("hello")
+		resp.chain.assertOK(t)
+	}
+
+	for _, ct := range []string{
+		"textual/plain",
+		"application/text",
+		"text/plain; charset=latin1",
+	} {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {ct}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString("hello")),
+		}
+
+		resp := NewResponse(reporter, httpResp)
+
+		resp.Text()
+		resp.chain.assertFailed(t)
+	}
+}
+
 func TestResponseForm(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -385,6 +786,36 @@ func TestResponseForm(t *testing.T) {
 	assert.Equal(t, expected, resp.Form().Raw())
 }
 
+func TestResponseFormMultiple(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	headers := map[string][]string{
+		"Content-Type": {"application/x-www-form-urlencoded"},
+	}
+
+	body := `a=1&b=2&b=3&c=x+y`
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header(headers),
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	expected := map[string]interface{}{
+		"a": "1",
+		"b": []interface{}{"2", "3"},
+		"c": "x y",
+	}
+
+	assert.Equal(t, expected, resp.Form().Raw())
+	resp.chain.assertOK(t)
+
+	resp.Form().Value("b").Array().Elements("2", "3")
+	resp.chain.assertOK(t)
+}
+
 func TestResponseFormBadBody(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -566,3 +997,207 @@ func TestResponseJSONCharsetBad(t *testing.T) {
 
 	assert.Equal(t, nil, resp.JSON().Raw())
 }
+
+func TestResponseJSONP(t *testing.T) {
+	bodies := []string{
+		`cb({"key": "value"})`,
+		`cb({"key": "value"});`,
+		` cb ( {"key": "value"} ) ; `,
+	}
+
+	for _, body := range bodies {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"application/javascript; charset=utf-8"},
+			},
+			Body: ioutil.NopCloser(bytes.NewBufferString(body)),
+		}
+
+		resp := NewResponse(reporter, httpResp)
+
+		resp.JSONP("cb")
+		resp.chain.assertOK(t)
+		resp.chain.reset()
+
+		resp.JSONP("other")
+		resp.chain.assertFailed(t)
+		resp.chain.reset()
+
+		resp.JSON()
+		resp.chain.assertFailed(t)
+		resp.chain.reset()
+
+		assert.Equal(t,
+			map[string]interface{}{"key": "value"}, resp.JSONP("cb").Raw())
+	}
+}
+
+func TestResponseJSONPBadBody(t *testing.T) {
+	bodies := []string{
+		``,
+		`cb`,
+		`cb(`,
+		`cb{"key": "value"}`,
+		`cb({"key": "value"}`,
+		`cb({"key": "value"});;`,
+		`cb({"key"})`,
+	}
+
+	for _, body := range bodies {
+		reporter := newMockReporter(t)
+
+		httpResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"application/javascript"},
+			},
+			Body: ioutil.NopCloser(bytes.NewBufferString(body)),
+		}
+
+		resp := NewResponse(reporter, httpResp)
+
+		resp.JSONP("cb")
+		resp.chain.assertFailed(t)
+		resp.chain.reset()
+
+		assert.True(t, resp.JSONP("cb").Raw() == nil)
+	}
+}
+
+func TestResponseJSONPBadType(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`cb({"key": "value"})`)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.JSONP("cb")
+	resp.chain.assertFailed(t)
+}
+
+func TestResponseJSONPTextJavascript(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"text/javascript; charset=utf-8"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`cb({"key": "value"})`)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	assert.Equal(t,
+		map[string]interface{}{"key": "value"}, resp.JSONP("cb").Raw())
+	resp.chain.assertOK(t)
+}
+
+func TestResponseJSONPEmptyCallback(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	httpResp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/javascript"},
+		},
+		Body: ioutil.NopCloser(bytes.NewBufferString(`({"key": "value"})`)),
+	}
+
+	resp := NewResponse(reporter, httpResp)
+
+	resp.JSONP("")
+	resp.chain.assertFailed(t)
+}
+
+func TestResponseMatchGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	update := false
+
+	savedUpdateGolden := updateGolden
+	updateGolden = func() bool {
+		return update
+	}
+	defer func() {
+		updateGolden = savedUpdateGolden
+	}()
+
+	reporter := newMockReporter(t)
+
+	makeResp := func(contentType, body string) *Response {
+		return NewResponse(reporter, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		})
+	}
+
+	jsonPath := filepath.Join(dir, "body.json")
+	textPath := filepath.Join(dir, "body.txt")
+
+	makeResp("application/json", `{"a":1}`).MatchGolden(jsonPath).
+		chain.assertFailed(t)
+
+	update = true
+
+	makeResp("application/json", `{"b":[1,2],"a":1}`).MatchGolden(jsonPath).
+		chain.assertOK(t)
+	makeResp("text/plain", "foo\nbar\n").MatchGolden(textPath).
+		chain.assertOK(t)
+
+	update = false
+
+	golden, err := ioutil.ReadFile(jsonPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"b\": [\n    1,\n    2\n  ],\n  \"a\": 1\n}\n", string(golden))
+
+	makeResp("application/json", `{"a": 1, "b": [1, 2]}`).MatchGolden(jsonPath).
+		chain.assertOK(t)
+	makeResp("application/json", `{"a": 1, "b": [2, 1]}`).MatchGolden(jsonPath).
+		chain.assertFailed(t)
+	makeResp("application/json", `{"a": `).MatchGolden(jsonPath).
+		chain.assertFailed(t)
+
+	makeResp("text/plain", "foo\nbar\n").MatchGolden(textPath).
+		chain.assertOK(t)
+	makeResp("text/plain", "foo\nbar").MatchGolden(textPath).
+		chain.assertFailed(t)
+	makeResp("text/plain", `{"a": 1, "b": [1, 2]}`).MatchGolden(jsonPath).
+		chain.assertFailed(t)
+
+	makeResp("application/problem+json", `{"a": 1, "b": [1, 2]}`).
+		MatchGolden(jsonPath).chain.assertOK(t)
+	makeResp("application/vnd.api+json; charset=utf-8", `{"b": [1, 2], "a": 1}`).
+		MatchGolden(jsonPath).chain.assertOK(t)
+}
+
+func TestResponseMatchGoldenEnv(t *testing.T) {
+	saved, ok := os.LookupEnv("UPDATE_GOLDEN")
+	defer func() {
+		if ok {
+			os.Setenv("UPDATE_GOLDEN", saved)
+		} else {
+			os.Unsetenv("UPDATE_GOLDEN")
+		}
+	}()
+
+	os.Setenv("UPDATE_GOLDEN", "1")
+	assert.True(t, updateGolden())
+
+	os.Unsetenv("UPDATE_GOLDEN")
+	assert.False(t, updateGolden())
+}
@@ -1,8 +1,15 @@
 package httpexpect
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // String provides methods to inspect attached string value
@@ -33,13 +40,63 @@ func (s *String) Raw() string {
 	return s.value
 }
 
+// Require returns a copy of String object that reports failures as fatal.
+// See Value.Require for details.
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.Require().Equal("Hello")
+func (s *String) Require() *String {
+	ret := *s
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Alias returns a copy of String object with assertion path replaced
+// by given name. See Value.Alias for details.
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.Alias("greeting").Equal("Hello")
+func (s *String) Alias(name string) *String {
+	ret := *s
+	ret.chain.alias(name)
+	return &ret
+}
+
+// Trim returns a new String object with leading and trailing whitespace
+// removed, as defined by Unicode.
+//
+// Example:
+//  str := NewString(t, "  Hello\n")
+//  str.Trim().Equal("Hello")
+//  str.Trim().EqualFold("HELLO")
+func (s *String) Trim() *String {
+	return &String{s.chain.enter(".Trim"), strings.TrimSpace(s.value)}
+}
+
+// Length returns a new Number object that may be used to inspect string
+// length, in bytes. Multi-byte UTF-8 characters are counted as several
+// bytes; use IsASCII to ensure they're absent.
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.Length().Equal(5)
+func (s *String) Length() *Number {
+	return &Number{s.chain.enter(".Length"), float64(len(s.value)), 0}
+}
+
 // Empty succeedes if string is empty.
 //
 // Example:
 //  str := NewString(t, "")
 //  str.Empty()
 func (s *String) Empty() *String {
-	return s.Equal("")
+	if len(s.value) != 0 {
+		s.chain.fail("\nexpected empty string, but got:\n  %s",
+			strconv.Quote(s.value))
+	}
+	return s
 }
 
 // NotEmpty succeedes if string is non-empty.
@@ -48,18 +105,32 @@ func (s *String) Empty() *String {
 //  str := NewString(t, "Hello")
 //  str.NotEmpty()
 func (s *String) NotEmpty() *String {
-	return s.NotEqual("")
+	if len(s.value) == 0 {
+		s.chain.fail("\nexpected non-empty string, but got empty string")
+	}
+	return s
 }
 
 // Equal succeedes if string is equal to another str.
 //
+// If any of the strings is multi-line, failure message includes
+// line-by-line diff.
+//
 // Example:
 //  str := NewString(t, "Hello")
 //  str.Equal("Hello")
 func (s *String) Equal(value string) *String {
 	if !(s.value == value) {
-		s.chain.fail("\nexpected string equal to:\n  %s\n\nbut got:\n  %s",
-			strconv.Quote(value), strconv.Quote(s.value))
+		if strings.Contains(value, "\n") || strings.Contains(s.value, "\n") {
+			s.chain.failValues("Equal", value, s.value,
+				"\nexpected string equal to:\n  %s\n\nbut got:\n  %s\n\ndiff:\n%s",
+				strconv.Quote(value), strconv.Quote(s.value),
+				diffLines(value, s.value))
+		} else {
+			s.chain.failValues("Equal", value, s.value,
+				"\nexpected string equal to:\n  %s\n\nbut got:\n  %s",
+				strconv.Quote(value), strconv.Quote(s.value))
+		}
 	}
 	return s
 }
@@ -71,7 +142,8 @@ func (s *String) Equal(value string) *String {
 //  str.NotEqual("Goodbye")
 func (s *String) NotEqual(value string) *String {
 	if !(s.value != value) {
-		s.chain.fail("\nexpected string NOT equal to:\n  %s", strconv.Quote(value))
+		s.chain.failValues("NotEqual", value, s.value,
+			"\nexpected string NOT equal to:\n  %s", strconv.Quote(value))
 	}
 	return s
 }
@@ -165,3 +237,394 @@ func (s *String) NotContainsFold(value string) *String {
 	}
 	return s
 }
+
+// HasPrefix succeedes if string begins with given prefix.
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.HasPrefix("Hel")
+func (s *String) HasPrefix(value string) *String {
+	if !strings.HasPrefix(s.value, value) {
+		s.chain.fail(
+			"\nexpected string with prefix:\n  %s\n\nbut got:\n  %s",
+			strconv.Quote(value), strconv.Quote(s.value))
+	}
+	return s
+}
+
+// HasPrefixFold succeedes if string begins with given prefix under Unicode
+// case-folding (case-insensitive match).
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.HasPrefixFold("hEL")
+func (s *String) HasPrefixFold(value string) *String {
+	if !hasPrefixFold(s.value, value) {
+		s.chain.fail(
+			"\nexpected string with prefix (case-insensitive):\n  %s"+
+				"\n\nbut got:\n  %s",
+			strconv.Quote(value), strconv.Quote(s.value))
+	}
+	return s
+}
+
+// HasSuffix succeedes if string ends with given suffix.
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.HasSuffix("llo")
+func (s *String) HasSuffix(value string) *String {
+	if !strings.HasSuffix(s.value, value) {
+		s.chain.fail(
+			"\nexpected string with suffix:\n  %s\n\nbut got:\n  %s",
+			strconv.Quote(value), strconv.Quote(s.value))
+	}
+	return s
+}
+
+// HasSuffixFold succeedes if string ends with given suffix under Unicode
+// case-folding (case-insensitive match).
+//
+// Example:
+//  str := NewString(t, "Hello")
+//  str.HasSuffixFold("LLo")
+func (s *String) HasSuffixFold(value string) *String {
+	if !hasSuffixFold(s.value, value) {
+		s.chain.fail(
+			"\nexpected string with suffix (case-insensitive):\n  %s"+
+				"\n\nbut got:\n  %s",
+			strconv.Quote(value), strconv.Quote(s.value))
+	}
+	return s
+}
+
+// hasPrefixFold compares prefix of s with the same number of runes as
+// in prefix using strings.EqualFold, since simple case folding maps every
+// rune to a single rune, but may change its length in bytes
+func hasPrefixFold(s, prefix string) bool {
+	n := utf8.RuneCountInString(prefix)
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return n == 0 && strings.EqualFold(s[:i], prefix)
+}
+
+// hasSuffixFold is like hasPrefixFold, but for suffix
+func hasSuffixFold(s, suffix string) bool {
+	n := utf8.RuneCountInString(suffix)
+	i := len(s)
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return n == 0 && strings.EqualFold(s[i:], suffix)
+}
+
+// IsASCII succeedes if string contains only ASCII characters (0x00-0x7F).
+//
+// Example:
+//  str := NewString(t, "hello-world")
+//  str.IsASCII()
+func (s *String) IsASCII() *String {
+	for i := 0; i < len(s.value); i++ {
+		if s.value[i] > unicode.MaxASCII {
+			s.chain.fail(
+				"\nexpected ASCII string, but got non-ASCII byte 0x%02x at offset %d:\n  %s",
+				s.value[i], i, strconv.Quote(s.value))
+			return s
+		}
+	}
+	return s
+}
+
+// IsUTF8 succeedes if string is valid UTF-8.
+//
+// Example:
+//  str := NewString(t, "привет")
+//  str.IsUTF8()
+func (s *String) IsUTF8() *String {
+	if utf8.ValidString(s.value) {
+		return s
+	}
+	for i := 0; i < len(s.value); {
+		r, size := utf8.DecodeRuneInString(s.value[i:])
+		if r == utf8.RuneError && size == 1 {
+			s.chain.fail(
+				"\nexpected valid UTF-8 string, but got invalid byte 0x%02x at offset %d:\n  %s",
+				s.value[i], i, strconv.Quote(s.value))
+			return s
+		}
+		i += size
+	}
+	return s
+}
+
+// Number parses string as a floating point number using strconv.ParseFloat
+// and returns a new Number object that may be used to inspect it.
+//
+// If string can't be parsed, failure is reported and zero number is returned.
+//
+// Example:
+//  str := NewString(t, "123.45")
+//  str.Number().Equal(123.45)
+func (s *String) Number() *Number {
+	if s.chain.failed() {
+		return &Number{s.chain, 0, 0}
+	}
+
+	value, err := strconv.ParseFloat(s.value, 64)
+	if err != nil {
+		s.chain.fail("\nexpected string containing number, but got:\n  %s",
+			strconv.Quote(s.value))
+		return &Number{s.chain, 0, 0}
+	}
+
+	return &Number{s.chain.enter(".Number"), value, 0}
+}
+
+// DateTime parses string as date and time and returns a new DateTime
+// object that may be used to inspect it.
+//
+// If layout is given, it's used for parsing. Otherwise, http.TimeFormat
+// is used. If string can't be parsed, failure is reported.
+//
+// Example:
+//  str := NewString(t, "Tue, 15 Nov 1994 08:12:31 GMT")
+//  str.DateTime().Lt(time.Now())
+//
+//  str := NewString(t, "15 Nov 94 08:12 GMT")
+//  str.DateTime(time.RFC822).Lt(time.Now())
+func (s *String) DateTime(layout ...string) *DateTime {
+	if s.chain.failed() {
+		return &DateTime{s.chain, time.Unix(0, 0)}
+	}
+
+	l := http.TimeFormat
+	if len(layout) != 0 {
+		l = layout[0]
+	}
+
+	value, err := time.Parse(l, s.value)
+	if err != nil {
+		s.chain.fail("\nexpected string containing datetime in format:\n  %s\n\n"+
+			"but got:\n  %s", strconv.Quote(l), strconv.Quote(s.value))
+		return &DateTime{s.chain, time.Unix(0, 0)}
+	}
+
+	return &DateTime{s.chain.enter(".DateTime"), value}
+}
+
+// Boolean parses string as a boolean using strconv.ParseBool and returns
+// a new Boolean object that may be used to inspect it.
+//
+// Accepted values are "1", "t", "T", "TRUE", "true", "True", "0", "f", "F",
+// "FALSE", "false", "False". If string can't be parsed, failure is reported
+// and false is returned.
+//
+// Example:
+//  str := NewString(t, "true")
+//  str.Boolean().True()
+func (s *String) Boolean() *Boolean {
+	if s.chain.failed() {
+		return &Boolean{s.chain, false}
+	}
+
+	value, err := strconv.ParseBool(s.value)
+	if err != nil {
+		s.chain.fail("\nexpected string containing boolean, but got:\n  %s",
+			strconv.Quote(s.value))
+		return &Boolean{s.chain, false}
+	}
+
+	return &Boolean{s.chain.enter(".Boolean"), value}
+}
+
+// JSON parses string as JSON and returns a new Value object that may be used
+// to inspect decoded value. It's useful for double-encoded JSON, when JSON
+// document is embedded into a string field.
+//
+// If string is not valid JSON, failure is reported and nil value is returned.
+//
+// Example:
+//  str := NewString(t, `{"foo": [1, 2]}`)
+//  str.JSON().Object().Value("foo").Array().Elements(1, 2)
+func (s *String) JSON() *Value {
+	if s.chain.failed() {
+		return &Value{s.chain, nil}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(s.value), &value); err != nil {
+		s.chain.fail("\nexpected string containing valid JSON, but got:\n  %s\n\n%s",
+			strconv.Quote(s.value), err.Error())
+		return &Value{s.chain, nil}
+	}
+
+	return &Value{s.chain.enter(".JSON"), value}
+}
+
+// Currency parses string as a formatted money amount and returns a new Number
+// object that may be used to inspect it.
+//
+// Leading and trailing currency symbols (e.g. "$", "€"), ISO 4217 codes
+// (e.g. "USD"), and whitespace are stripped, and then thousands separators
+// are removed. By default, "." is used as decimal mark and "," as thousands
+// separator. If decimalMark is "," (as in many European locales), "." and ","
+// swap their meaning. Apostrophe and whitespace are always treated as
+// thousands separators. Thousands separators are accepted only between
+// groups of three digits.
+//
+// If string can't be parsed, failure is reported and zero number is returned.
+//
+// Example:
+//  str := NewString(t, "$1,234.56")
+//  str.Currency().Equal(1234.56)
+//
+//  str := NewString(t, "1.234,56 EUR")
+//  str.Currency(",").Equal(1234.56)
+func (s *String) Currency(decimalMark ...string) *Number {
+	if s.chain.failed() {
+		return &Number{s.chain, 0, 0}
+	}
+
+	mark := "."
+	if len(decimalMark) != 0 {
+		mark = decimalMark[0]
+	}
+
+	if mark != "." && mark != "," {
+		s.chain.fail("\nunsupported decimal mark %s, expected \".\" or \",\"",
+			strconv.Quote(mark))
+		return &Number{s.chain, 0, 0}
+	}
+
+	value, err := parseCurrency(s.value, mark)
+	if err != nil {
+		s.chain.fail("\nexpected currency string, but got:\n  %s\n\n%s",
+			strconv.Quote(s.value), err.Error())
+		return &Number{s.chain, 0, 0}
+	}
+
+	return &Number{s.chain.enter(".Currency"), value, 0}
+}
+
+func parseCurrency(str string, mark string) (float64, error) {
+	separator := ","
+	if mark == "," {
+		separator = "."
+	}
+
+	amount := trimCurrency(str)
+
+	sign := ""
+	if strings.HasPrefix(amount, "-") || strings.HasPrefix(amount, "+") {
+		sign, amount = amount[:1], trimCurrency(amount[1:])
+	}
+
+	intPart, fracPart := amount, ""
+	if i := strings.Index(amount, mark); i >= 0 {
+		intPart, fracPart = amount[:i], amount[i+1:]
+		if fracPart == "" {
+			return 0, fmt.Errorf("no digits after decimal mark %q", mark)
+		}
+	}
+
+	if intPart == "" && fracPart == "" {
+		return 0, errors.New("no digits in amount")
+	}
+
+	for _, r := range fracPart {
+		if string(r) == mark {
+			return 0, fmt.Errorf("repeated decimal mark %q", mark)
+		}
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("unexpected character %q after decimal mark", r)
+		}
+	}
+
+	groups := splitDigitGroups(intPart, separator)
+
+	for _, g := range groups {
+		for _, r := range g {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+
+	if len(groups) > 1 {
+		for i, g := range groups {
+			if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i > 0 && len(g) != 3) {
+				return 0, errors.New(
+					"thousands separators should divide digits into groups of three")
+			}
+		}
+	}
+
+	digits := strings.Join(groups, "")
+	if digits == "" {
+		digits = "0"
+	}
+
+	cleaned := sign + digits
+	if fracPart != "" {
+		cleaned += "." + fracPart
+	}
+
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// trimCurrency strips currency symbols, ISO 4217 codes, and whitespace
+// from both ends of string
+func trimCurrency(s string) string {
+	for {
+		t := strings.TrimFunc(s, func(r rune) bool {
+			return unicode.Is(unicode.Sc, r) || unicode.IsSpace(r)
+		})
+		if n := len(t); n >= 3 && isCurrencyCode(t[:3]) &&
+			(n == 3 || !isUpperASCII(t[3])) {
+			t = t[3:]
+		}
+		if n := len(t); n >= 3 && isCurrencyCode(t[n-3:]) &&
+			(n == 3 || !isUpperASCII(t[n-4])) {
+			t = t[:n-3]
+		}
+		if t == s {
+			return t
+		}
+		s = t
+	}
+}
+
+func isCurrencyCode(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isUpperASCII(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpperASCII(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
+
+// splitDigitGroups splits integer part of amount by thousands separators
+func splitDigitGroups(s string, separator string) []string {
+	var groups []string
+	start := 0
+	for i, r := range s {
+		if string(r) == separator || r == '\'' || unicode.IsSpace(r) {
+			groups = append(groups, s[start:i])
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	return append(groups, s[start:])
+}
@@ -3,6 +3,7 @@ package httpexpect
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestStringFailed(t *testing.T) {
@@ -22,6 +23,21 @@ func TestStringFailed(t *testing.T) {
 	value.NotContains("")
 	value.ContainsFold("")
 	value.NotContainsFold("")
+	value.HasPrefix("")
+	value.HasSuffix("")
+	value.HasPrefixFold("")
+	value.HasSuffixFold("")
+	value.Currency()
+	value.IsASCII()
+	value.IsUTF8()
+
+	value.Number().chain.assertFailed(t)
+	value.Boolean().chain.assertFailed(t)
+	value.JSON().chain.assertFailed(t)
+	value.Trim().chain.assertFailed(t)
+	value.Length().chain.assertFailed(t)
+
+	value.DateTime().chain.assertFailed(t)
 }
 
 func TestStringEmpty(t *testing.T) {
@@ -46,6 +62,16 @@ func TestStringEmpty(t *testing.T) {
 	value2.NotEmpty()
 	value2.chain.assertOK(t)
 	value2.chain.reset()
+
+	value3 := NewString(reporter, " ")
+
+	value3.Empty()
+	value3.chain.assertFailed(t)
+	value3.chain.reset()
+
+	value3.Trim().Empty()
+	value3.chain.assertOK(t)
+	value3.chain.reset()
 }
 
 func TestStringEqual(t *testing.T) {
@@ -72,6 +98,33 @@ func TestStringEqual(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestStringEqualMultiline(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	value := NewString(collector, "<p>\nfoo\n</p>")
+
+	value.Equal("<p>\nfoo\n</p>")
+	value.chain.assertOK(t)
+
+	value.Equal("<p>\nbar\n</p>")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.Equal("foo")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewString(collector, "foo").Equal("bar")
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 3, len(failures))
+	assert.Contains(t, failures[0].Message, "\ndiff:\n--- expected\n+++ actual\n")
+	assert.Contains(t, failures[0].Message, "\n-bar\n+foo\n")
+	assert.Contains(t, failures[1].Message, "\ndiff:\n")
+	assert.NotContains(t, failures[2].Message, "\ndiff:\n")
+}
+
 func TestStringEqualFold(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -102,6 +155,25 @@ func TestStringEqualFold(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestStringTrim(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, " \t Hello, World\r\n")
+
+	value.Trim().Equal("Hello, World")
+	value.Trim().EqualFold("hello, world")
+	value.chain.assertOK(t)
+
+	assert.Equal(t, " \t Hello, World\r\n", value.Raw())
+
+	trimmed := value.Trim()
+	trimmed.Equal("HELLO, WORLD")
+	trimmed.chain.assertFailed(t)
+	value.chain.assertOK(t)
+
+	NewString(reporter, "\u00a0X\u2003").Trim().Equal("X").chain.assertOK(t)
+}
+
 func TestStringContains(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -124,6 +196,104 @@ func TestStringContains(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestStringHasPrefixSuffix(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, "https://example.com")
+
+	value.HasPrefix("https://")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasPrefix("http://")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasPrefix("HTTPS://")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasSuffix(".com")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasSuffix(".org")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasSuffix(".COM")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasPrefix("")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasSuffix("")
+	value.chain.assertOK(t)
+	value.chain.reset()
+}
+
+func TestStringHasPrefixSuffixFold(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, "https://example.com")
+
+	value.HasPrefixFold("HTTPS://")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasPrefixFold("HTTP://")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasSuffixFold(".COM")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasSuffixFold(".ORG")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasPrefixFold("https://example.com/")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasSuffixFold("/https://example.com")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	// Kelvin sign folds to "k", long s folds to "s", but both have
+	// different length in bytes
+	value = NewString(reporter, "\u212aelvin \u017ftraße")
+
+	value.HasPrefixFold("KEL")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasPrefixFold("kelvin s")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasSuffixFold("STRAßE")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasSuffixFold("strasse")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value = NewString(reporter, "kelvin")
+
+	value.HasPrefixFold("\u212a")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.HasSuffixFold("VIN")
+	value.chain.assertOK(t)
+	value.chain.reset()
+}
+
 func TestStringContainsFold(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -153,3 +323,243 @@ func TestStringContainsFold(t *testing.T) {
 	value.chain.assertOK(t)
 	value.chain.reset()
 }
+
+func TestStringIsASCII(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, str := range []string{"", "hello-world_123", "\x00\x7f"} {
+		value := NewString(reporter, str)
+
+		value.IsASCII()
+		value.chain.assertOK(t)
+	}
+
+	for _, str := range []string{"привет", "abc\x80", "caf\u00e9"} {
+		value := NewString(reporter, str)
+
+		value.IsASCII()
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestStringIsUTF8(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, str := range []string{"", "hello", "привет", "\ufffd"} {
+		value := NewString(reporter, str)
+
+		value.IsUTF8()
+		value.chain.assertOK(t)
+	}
+
+	for _, str := range []string{"\xff", "abc\xc3", "\xe2\x28\xa1"} {
+		value := NewString(reporter, str)
+
+		value.IsUTF8()
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestStringNumber(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := map[string]float64{
+		"123":    123,
+		"-12.5":  -12.5,
+		"1e3":    1000,
+		"0.0001": 0.0001,
+	}
+
+	for str, num := range cases {
+		value := NewString(reporter, str)
+
+		value.Number().Equal(num).chain.assertOK(t)
+		value.chain.assertOK(t)
+	}
+
+	for _, str := range []string{"", "abc", "12a", " 12", "1,5"} {
+		value := NewString(reporter, str)
+
+		value.Number().chain.assertFailed(t)
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestStringBoolean(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	for _, str := range []string{"true", "True", "1", "t"} {
+		value := NewString(reporter, str)
+
+		value.Boolean().True().chain.assertOK(t)
+		value.chain.assertOK(t)
+	}
+
+	for _, str := range []string{"false", "FALSE", "0", "f"} {
+		value := NewString(reporter, str)
+
+		value.Boolean().False().chain.assertOK(t)
+		value.chain.assertOK(t)
+	}
+
+	for _, str := range []string{"", "yes", "2"} {
+		value := NewString(reporter, str)
+
+		value.Boolean().chain.assertFailed(t)
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestStringJSON(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewString(reporter, `{"foo": [1, "bar"], "baz": null}`)
+
+	value1.JSON().Object().Value("foo").Array().Elements(1, "bar")
+	value1.JSON().Object().Value("baz").Null()
+	value1.chain.assertOK(t)
+
+	value2 := NewString(reporter, `"str"`)
+
+	value2.JSON().String().Equal("str")
+	value2.chain.assertOK(t)
+
+	for _, str := range []string{"", "{", `{"foo": }`, "str"} {
+		value := NewString(reporter, str)
+
+		assert.True(t, value.JSON().Raw() == nil)
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestStringCurrency(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewString(reporter, "$1,234.56")
+
+	assert.Equal(t, 1234.56, value1.Currency().Raw())
+	value1.chain.assertOK(t)
+	value1.chain.reset()
+
+	assert.Equal(t, "Currency", value1.Currency().chain.path)
+
+	value2 := NewString(reporter, "-1 234,5 \u20ac")
+
+	assert.Equal(t, -1234.5, value2.Currency(",").Raw())
+	value2.chain.assertOK(t)
+	value2.chain.reset()
+
+	value3 := NewString(reporter, "CHF 1'234.50")
+
+	assert.Equal(t, 1234.5, value3.Currency().Raw())
+	value3.chain.assertOK(t)
+	value3.chain.reset()
+
+	value4 := NewString(reporter, "1'000 \u00a3")
+
+	assert.Equal(t, 1000.0, value4.Currency().Raw())
+	value4.chain.assertOK(t)
+	value4.chain.reset()
+
+	value5 := NewString(reporter, "$")
+
+	value5.Currency()
+	value5.chain.assertFailed(t)
+	value5.chain.reset()
+
+	value6 := NewString(reporter, "1.234,56")
+
+	value6.Currency()
+	value6.chain.assertFailed(t)
+	value6.chain.reset()
+
+	value1.Currency("-")
+	value1.chain.assertFailed(t)
+	value1.chain.reset()
+
+	good := map[string]float64{
+		"1,234.56 USD": 1234.56,
+		"EUR 10":       10,
+		"USD-5":        -5,
+		"-$0.5":        -0.5,
+		"$.5":          0.5,
+		"12,345,678":   12345678,
+		"1234":         1234,
+	}
+
+	for str, expected := range good {
+		value := NewString(reporter, str)
+		assert.Equal(t, expected, value.Currency().Raw(), str)
+		value.chain.assertOK(t)
+	}
+
+	bad := []string{
+		"1,23.45",
+		"1,2345",
+		",234",
+		"1,,234",
+		"1..5",
+		"5.",
+		"USD",
+		"1.5 US",
+		"1.5 USDX",
+		"12abc",
+	}
+
+	for _, str := range bad {
+		value := NewString(reporter, str)
+		value.Currency()
+		value.chain.assertFailed(t)
+	}
+
+	collector := NewFailureCollector(nil)
+
+	NewString(collector, "1..5").Currency()
+	NewString(collector, "1,23.45").Currency()
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 2, len(failures))
+	assert.Contains(t, failures[0].Message, `repeated decimal mark "."`)
+	assert.Contains(t, failures[1].Message, "groups of three")
+}
+
+func TestStringDateTime(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value1 := NewString(reporter, "Tue, 15 Nov 1994 08:12:31 GMT")
+	dt1 := value1.DateTime()
+	value1.chain.assertOK(t)
+	dt1.chain.assertOK(t)
+	assert.True(t, time.Date(1994, 11, 15, 8, 12, 31, 0, time.UTC).Equal(dt1.Raw()))
+
+	value2 := NewString(reporter, "15 Nov 94 08:12 GMT")
+	dt2 := value2.DateTime(time.RFC822)
+	value2.chain.assertOK(t)
+	dt2.chain.assertOK(t)
+	assert.True(t, time.Date(1994, 11, 15, 8, 12, 0, 0, time.UTC).Equal(dt2.Raw()))
+
+	value3 := NewString(reporter, "bad")
+	dt3 := value3.DateTime()
+	value3.chain.assertFailed(t)
+	dt3.chain.assertFailed(t)
+}
+
+func TestStringLength(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewString(reporter, "")
+
+	value.Length().Equal(0)
+	value.chain.assertOK(t)
+
+	value = NewString(reporter, "Hello")
+
+	value.Length().Equal(5)
+	value.chain.assertOK(t)
+
+	value = NewString(reporter, "Привет")
+
+	value.Length().Equal(12)
+	value.chain.assertOK(t)
+}
@@ -0,0 +1,135 @@
+package httpexpect
+
+import (
+	"github.com/gavv/monotime"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing provides methods to inspect durations of request round-trip
+// phases: DNS lookup, TCP connect, TLS handshake, and time to first byte.
+//
+// Timing is available only if Config.EnableTiming is set. TLS handshake
+// duration requires Go 1.8 or later; with older versions, it's always zero.
+type Timing struct {
+	chain   chain
+	dns     time.Duration
+	connect time.Duration
+	tls     time.Duration
+	ttfb    time.Duration
+}
+
+// DNS returns a new Number object that may be used to inspect DNS lookup
+// duration, in nanoseconds. It's zero if lookup was not performed, e.g.
+// if connection was reused or address is an IP.
+//
+// Example:
+//  timing := resp.Timing()
+//  timing.DNS().Lt(float64(time.Millisecond * 100))
+func (t *Timing) DNS() *Number {
+	return &Number{t.chain.enter(".DNS"), float64(t.dns), 0}
+}
+
+// Connect returns a new Number object that may be used to inspect TCP
+// connect duration, in nanoseconds. It's zero if connection was reused.
+//
+// Example:
+//  timing := resp.Timing()
+//  timing.Connect().Lt(float64(time.Millisecond * 100))
+func (t *Timing) Connect() *Number {
+	return &Number{t.chain.enter(".Connect"), float64(t.connect), 0}
+}
+
+// TLS returns a new Number object that may be used to inspect TLS handshake
+// duration, in nanoseconds. It's zero for plain HTTP requests and reused
+// connections.
+//
+// Example:
+//  timing := resp.Timing()
+//  timing.TLS().Lt(float64(time.Millisecond * 100))
+func (t *Timing) TLS() *Number {
+	return &Number{t.chain.enter(".TLS"), float64(t.tls), 0}
+}
+
+// TimeToFirstByte returns a new Number object that may be used to inspect
+// duration between sending the request and receiving the first byte of the
+// response, in nanoseconds. It includes durations of all other phases.
+//
+// Example:
+//  timing := resp.Timing()
+//  timing.TimeToFirstByte().Lt(float64(time.Millisecond * 500))
+func (t *Timing) TimeToFirstByte() *Number {
+	return &Number{t.chain.enter(".TimeToFirstByte"), float64(t.ttfb), 0}
+}
+
+// timingTrace holds monotonic timestamps of request phases; zero
+// timestamp means that the event didn't happen
+type timingTrace struct {
+	mutex        sync.Mutex
+	start        time.Duration
+	dnsStart     time.Duration
+	dnsDone      time.Duration
+	connectStart time.Duration
+	connectDone  time.Duration
+	tlsStart     time.Duration
+	tlsDone      time.Duration
+	firstByte    time.Duration
+}
+
+func newTimingTrace() *timingTrace {
+	return &timingTrace{start: monotime.Now()}
+}
+
+func (tt *timingTrace) clientTrace() *httptrace.ClientTrace {
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			tt.mark(&tt.dnsStart, false)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			tt.mark(&tt.dnsDone, true)
+		},
+		ConnectStart: func(string, string) {
+			tt.mark(&tt.connectStart, false)
+		},
+		ConnectDone: func(string, string, error) {
+			tt.mark(&tt.connectDone, true)
+		},
+		GotFirstResponseByte: func() {
+			tt.mark(&tt.firstByte, false)
+		},
+	}
+	tt.traceTLS(trace)
+	return trace
+}
+
+// mark records current time into ts. If last is false, only the first
+// event is recorded; otherwise, the last one.
+func (tt *timingTrace) mark(ts *time.Duration, last bool) {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	if last || *ts == 0 {
+		*ts = monotime.Now()
+	}
+}
+
+func (tt *timingTrace) makeTiming(chain chain) *Timing {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	return &Timing{
+		chain:   chain,
+		dns:     timingSpan(tt.dnsStart, tt.dnsDone),
+		connect: timingSpan(tt.connectStart, tt.connectDone),
+		tls:     timingSpan(tt.tlsStart, tt.tlsDone),
+		ttfb:    timingSpan(tt.start, tt.firstByte),
+	}
+}
+
+func timingSpan(start, end time.Duration) time.Duration {
+	if start == 0 || end == 0 || end < start {
+		return 0
+	}
+	return end - start
+}
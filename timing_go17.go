@@ -0,0 +1,11 @@
+// +build !go1.8
+
+package httpexpect
+
+import (
+	"net/http/httptrace"
+)
+
+// TLS handshake hooks were added to httptrace.ClientTrace in Go 1.8
+func (tt *timingTrace) traceTLS(trace *httptrace.ClientTrace) {
+}
@@ -0,0 +1,17 @@
+// +build go1.8
+
+package httpexpect
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+)
+
+func (tt *timingTrace) traceTLS(trace *httptrace.ClientTrace) {
+	trace.TLSHandshakeStart = func() {
+		tt.mark(&tt.tlsStart, false)
+	}
+	trace.TLSHandshakeDone = func(tls.ConnectionState, error) {
+		tt.mark(&tt.tlsDone, true)
+	}
+}
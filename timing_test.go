@@ -0,0 +1,69 @@
+package httpexpect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimingGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	start := time.Duration(100)
+
+	trace := &timingTrace{
+		start:        start,
+		dnsStart:     start + 1,
+		dnsDone:      start + 3,
+		connectStart: start + 4,
+		connectDone:  start + 7,
+		firstByte:    start + 20,
+	}
+
+	timing := trace.makeTiming(makeChain(reporter))
+
+	assert.Equal(t, 2.0, timing.DNS().Raw())
+	assert.Equal(t, 3.0, timing.Connect().Raw())
+	assert.Equal(t, 0.0, timing.TLS().Raw())
+	assert.Equal(t, 20.0, timing.TimeToFirstByte().Raw())
+
+	timing.chain.assertOK(t)
+}
+
+func TestTimingLive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Millisecond * 10)
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:      server.URL,
+		EnableTiming: true,
+		Reporter:     NewAssertReporter(t),
+	})
+
+	timing := e.GET("/").Expect().Status(http.StatusOK).Timing()
+
+	timing.chain.assertOK(t)
+
+	assert.True(t, timing.Connect().Raw() > 0)
+	assert.Equal(t, 0.0, timing.TLS().Raw())
+	assert.True(t, timing.TimeToFirstByte().Raw() >= float64(time.Millisecond*10))
+}
+
+func TestTimingDisabled(t *testing.T) {
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	}
+
+	resp := NewRequest(config, "GET", "url").Expect()
+	resp.chain.assertOK(t)
+
+	resp.Timing()
+	resp.chain.assertFailed(t)
+}
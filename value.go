@@ -1,5 +1,9 @@
 package httpexpect
 
+import (
+	"reflect"
+)
+
 // Value provides methods to inspect attached interface{} object
 // (Go representation of arbitrary JSON value) and cast it to
 // concrete type.
@@ -45,6 +49,38 @@ func (v *Value) Raw() interface{} {
 	return v.value
 }
 
+// Require returns a copy of Value object, which reports failures as fatal,
+// i.e. aborts the test on failure. Objects obtained from the copy also
+// report failures as fatal. Original object is not modified.
+//
+// Reporter should implement FatalReporter; otherwise, failures are reported
+// using Errorf as usual.
+//
+// Example:
+//  value := NewValue(t, 123)
+//  value.Require().Number().Equal(123)
+func (v *Value) Require() *Value {
+	ret := *v
+	ret.chain.fatal = true
+	return &ret
+}
+
+// Alias returns a copy of Value object with assertion path replaced by
+// given name. Failures of the copy and of objects obtained from it report
+// the name instead of the auto-generated path, e.g. "primaryEmail" or
+// "primaryEmail.String" instead of `JSON.Object["users"].Array[0]...`.
+// Original object is not modified.
+//
+// Example:
+//  user := resp.JSON().Object().Value("users").Array().First().Object()
+//  email := user.Value("emails").Array().First().Alias("primaryEmail")
+//  email.String().Contains("@")
+func (v *Value) Alias(name string) *Value {
+	ret := *v
+	ret.chain.alias(name)
+	return &ret
+}
+
 // Object returns a new Object attached to underlying value.
 //
 // If underlying value is not an object (map[string]interface{}), failure is reported
@@ -59,7 +95,7 @@ func (v *Value) Object() *Object {
 		v.chain.fail("\nexpected object value (map or struct), but got:\n%s",
 			dumpValue(v.value))
 	}
-	return &Object{v.chain, data}
+	return &Object{v.chain.enter(".Object"), data}
 }
 
 // Array returns a new Array attached to underlying value.
@@ -76,7 +112,7 @@ func (v *Value) Array() *Array {
 		v.chain.fail("\nexpected array value, but got:\n%s",
 			dumpValue(v.value))
 	}
-	return &Array{v.chain, data}
+	return &Array{v.chain.enter(".Array"), data}
 }
 
 // String returns a new String attached to underlying value.
@@ -93,7 +129,7 @@ func (v *Value) String() *String {
 		v.chain.fail("\nexpected string value, but got:\n%s",
 			dumpValue(v.value))
 	}
-	return &String{v.chain, data}
+	return &String{v.chain.enter(".String"), data}
 }
 
 // Number returns a new Number attached to underlying value.
@@ -110,7 +146,7 @@ func (v *Value) Number() *Number {
 		v.chain.fail("\nexpected numeric value, but got:\n%s",
 			dumpValue(v.value))
 	}
-	return &Number{v.chain, data}
+	return &Number{v.chain.enter(".Number"), data, 0}
 }
 
 // Boolean returns a new Boolean attached to underlying value.
@@ -127,7 +163,7 @@ func (v *Value) Boolean() *Boolean {
 		v.chain.fail("\nexpected boolean value, but got:\n%s",
 			dumpValue(v.value))
 	}
-	return &Boolean{v.chain, data}
+	return &Boolean{v.chain.enter(".Boolean"), data}
 }
 
 // Null succeedes if value is nil.
@@ -177,3 +213,173 @@ func (v *Value) NotNull() *Value {
 	}
 	return v
 }
+
+// Equal succeedes if value is equal to another value.
+// Before comparison, both values are converted to canonical form.
+//
+// Example:
+//  value := NewValue(t, map[string]interface{}{"foo": 123})
+//  value.Equal(map[string]interface{}{"foo": 123})
+//
+//  value := NewValue(t, "foo")
+//  value.Equal("foo")
+func (v *Value) Equal(value interface{}) *Value {
+	expected, ok := canonValue(&v.chain, value)
+	if !ok {
+		return v
+	}
+	actual, ok := canonValue(&v.chain, v.value)
+	if !ok {
+		return v
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		v.chain.failValues("Equal", expected, actual,
+			"\nexpected value equal to:\n%s\n\nbut got:\n%s\n\ndiff:\n%s",
+			dumpValue(expected),
+			dumpValue(actual),
+			diffValues(expected, actual))
+	}
+	return v
+}
+
+// EqualJSON is like Equal, but expected value is given as JSON literal.
+// If literal is not valid JSON, failure is reported.
+//
+// Example:
+//  resp := NewResponse(t, response)
+//  resp.JSON().EqualJSON(`{"users": [{"name": "john"}]}`)
+func (v *Value) EqualJSON(literal string) *Value {
+	if v.chain.failed() {
+		return v
+	}
+	value, ok := parseJSON(&v.chain, literal)
+	if !ok {
+		return v
+	}
+	return v.Equal(value)
+}
+
+// NotEqual succeedes if value is not equal to another value.
+// Before comparison, both values are converted to canonical form.
+//
+// Example:
+//  value := NewValue(t, map[string]interface{}{"foo": 123})
+//  value.NotEqual(map[string]interface{}{"bar": 123})
+//
+//  value := NewValue(t, "foo")
+//  value.NotEqual("bar")
+func (v *Value) NotEqual(value interface{}) *Value {
+	expected, ok := canonValue(&v.chain, value)
+	if !ok {
+		return v
+	}
+	actual, ok := canonValue(&v.chain, v.value)
+	if !ok {
+		return v
+	}
+	if reflect.DeepEqual(expected, actual) {
+		v.chain.failValues("NotEqual", expected, actual,
+			"\nexpected value NOT equal to:\n%s",
+			dumpValue(expected))
+	}
+	return v
+}
+
+// Decode unmarshals underlying value into target, using JSON round-trip
+// conversion. target should be a non-nil pointer, e.g. to a struct.
+//
+// If conversion fails, Decode reports failure and target may be partially
+// filled.
+//
+// Example:
+//  type User struct {
+//      Name string `json:"name"`
+//  }
+//
+//  var user User
+//  resp.JSON().Decode(&user)
+func (v *Value) Decode(target interface{}) *Value {
+	if v.chain.failed() {
+		return v
+	}
+	decodeValue(&v.chain, v.value, target)
+	return v
+}
+
+// Type returns JSON type name of underlying value, converted to canonical
+// form. Unlike Object(), Array(), and other casts, it never reports failure.
+//
+// Returned value is one of "object", "array", "string", "number", "boolean",
+// or "null". If value can't be converted to JSON, empty string is returned.
+//
+// Example:
+//  value := NewValue(t, []interface{}{"foo", 123})
+//  assert.Equal(t, "array", value.Type())
+func (v *Value) Type() string {
+	return canonType(v.value)
+}
+
+// IsObject returns true if underlying value is an object (map or struct).
+// It never reports failure.
+//
+// Example:
+//  value := NewValue(t, map[string]interface{}{"foo": 123})
+//  if value.IsObject() {
+//      value.Object().ContainsKey("foo")
+//  }
+func (v *Value) IsObject() bool {
+	return v.Type() == "object"
+}
+
+// IsArray returns true if underlying value is an array.
+// It never reports failure.
+//
+// Example:
+//  value := NewValue(t, []interface{}{"foo", 123})
+//  assert.True(t, value.IsArray())
+func (v *Value) IsArray() bool {
+	return v.Type() == "array"
+}
+
+// IsString returns true if underlying value is a string.
+// It never reports failure.
+//
+// Example:
+//  value := NewValue(t, "foo")
+//  assert.True(t, value.IsString())
+func (v *Value) IsString() bool {
+	return v.Type() == "string"
+}
+
+// IsNumber returns true if underlying value is a number.
+// It never reports failure.
+//
+// Example:
+//  value := NewValue(t, 123)
+//  assert.True(t, value.IsNumber())
+func (v *Value) IsNumber() bool {
+	return v.Type() == "number"
+}
+
+// IsBoolean returns true if underlying value is a bool.
+// It never reports failure.
+//
+// Example:
+//  value := NewValue(t, true)
+//  assert.True(t, value.IsBoolean())
+func (v *Value) IsBoolean() bool {
+	return v.Type() == "boolean"
+}
+
+// IsNull returns true if underlying value is nil.
+// It never reports failure.
+//
+// Like Null(), it treats non-nil interface{} that points to nil value (e.g.
+// nil slice or map) as null value.
+//
+// Example:
+//  value := NewValue(t, nil)
+//  assert.True(t, value.IsNull())
+func (v *Value) IsNull() bool {
+	return v.Type() == "null"
+}
@@ -28,6 +28,136 @@ func TestValueFailed(t *testing.T) {
 
 	value.Null()
 	value.NotNull()
+	value.Equal(nil)
+	value.NotEqual(nil)
+	value.EqualJSON("null")
+
+	var target interface{}
+	value.Decode(&target)
+}
+
+func TestValueEqual(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cases := []struct {
+		value interface{}
+		equal interface{}
+		other interface{}
+	}{
+		{nil, nil, false},
+		{true, true, false},
+		{123, 123.0, "123"},
+		{"foo", "foo", "bar"},
+		{
+			[]interface{}{"foo", 123},
+			[]interface{}{"foo", 123.0},
+			[]interface{}{"foo", "123"},
+		},
+		{
+			map[string]interface{}{"foo": []interface{}{1, 2}},
+			map[string][]int{"foo": {1, 2}},
+			map[string]interface{}{"foo": []interface{}{2, 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		value := NewValue(reporter, tc.value)
+
+		value.Equal(tc.equal)
+		value.chain.assertOK(t)
+		value.chain.reset()
+
+		value.NotEqual(tc.equal)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+
+		value.NotEqual(tc.other)
+		value.chain.assertOK(t)
+		value.chain.reset()
+
+		value.Equal(tc.other)
+		value.chain.assertFailed(t)
+		value.chain.reset()
+	}
+
+	value := NewValue(reporter, "foo")
+
+	value.Equal(make(chan int))
+	value.chain.assertFailed(t)
+	value.chain.reset()
+}
+
+func TestValueEqualJSON(t *testing.T) {
+	collector := NewFailureCollector(nil)
+
+	value := NewValue(collector, map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"name": "john", "id": 1},
+		},
+	})
+
+	value.EqualJSON(`{"users": [{"id": 1, "name": "john"}]}`)
+	value.chain.assertOK(t)
+
+	value.EqualJSON(`{"users": [{"id": 2, "name": "john"}]}`)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.EqualJSON(`{"users": `)
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	failures := collector.Failures()
+
+	assert.Equal(t, 2, len(failures))
+	assert.Contains(t, failures[0].Message, "\ndiff:\n--- expected\n+++ actual\n")
+	assert.Contains(t, failures[1].Message, "invalid JSON literal")
+
+	NewValue(collector, "foo").EqualJSON(`"foo"`).chain.assertOK(t)
+	NewValue(collector, nil).EqualJSON(`null`).chain.assertOK(t)
+}
+
+func TestValueDecode(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	value1 := NewValue(reporter, map[string]interface{}{
+		"name": "John",
+		"age":  30,
+	})
+
+	var user User
+	value1.Decode(&user)
+	value1.chain.assertOK(t)
+
+	assert.Equal(t, User{"John", 30}, user)
+
+	value2 := NewValue(reporter, []interface{}{1, 2})
+
+	var numbers []int
+	value2.Decode(&numbers)
+	value2.chain.assertOK(t)
+
+	assert.Equal(t, []int{1, 2}, numbers)
+
+	value3 := NewValue(reporter, "foo")
+
+	var number int
+	value3.Decode(&number)
+	value3.chain.assertFailed(t)
+	value3.chain.reset()
+
+	value3.Decode(nil)
+	value3.chain.assertFailed(t)
+	value3.chain.reset()
+
+	value3.Decode(number)
+	value3.chain.assertFailed(t)
+	value3.chain.reset()
 }
 
 func TestValueCastNull(t *testing.T) {
@@ -257,3 +387,45 @@ func TestValueGetBoolean(t *testing.T) {
 	inner2.chain.reset()
 	assert.Equal(t, false, inner2.Raw())
 }
+
+func TestValueType(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	type (
+		myMap map[string]interface{}
+		myInt int
+	)
+
+	values := []struct {
+		data     interface{}
+		expected string
+	}{
+		{map[string]interface{}{}, "object"},
+		{myMap{}, "object"},
+		{struct{}{}, "object"},
+		{[]interface{}{}, "array"},
+		{[]int{1, 2}, "array"},
+		{"", "string"},
+		{123, "number"},
+		{myInt(123), "number"},
+		{false, "boolean"},
+		{nil, "null"},
+		{[]interface{}(nil), "null"},
+		{func() {}, ""},
+	}
+
+	for _, v := range values {
+		value := NewValue(reporter, v.data)
+
+		assert.Equal(t, v.expected, value.Type())
+
+		assert.Equal(t, v.expected == "object", value.IsObject())
+		assert.Equal(t, v.expected == "array", value.IsArray())
+		assert.Equal(t, v.expected == "string", value.IsString())
+		assert.Equal(t, v.expected == "number", value.IsNumber())
+		assert.Equal(t, v.expected == "boolean", value.IsBoolean())
+		assert.Equal(t, v.expected == "null", value.IsNull())
+
+		value.chain.assertOK(t)
+	}
+}
@@ -0,0 +1,284 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	"time"
+)
+
+// Websocket provides methods to read from, write into and close WebSocket
+// connection.
+type Websocket struct {
+	chain        chain
+	conn         *websocket.Conn
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	isClosed     bool
+}
+
+// NewWebsocket returns a new Websocket object given a reporter used to
+// report failures and websocket.Conn to be inspected and handled.
+//
+// reporter and conn should not be nil.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  defer ws.Close()
+//
+//  ws.WriteText("hello")
+//  ws.Expect().Text().Equal("hello")
+func NewWebsocket(reporter Reporter, conn *websocket.Conn) *Websocket {
+	chain := makeChain(reporter)
+	if conn == nil {
+		chain.fail("expected non-nil websocket connection")
+	}
+	return makeWebsocket(chain, conn)
+}
+
+func makeWebsocket(chain chain, conn *websocket.Conn) *Websocket {
+	return &Websocket{
+		chain: chain,
+		conn:  conn,
+	}
+}
+
+// Raw returns underlying websocket.Conn object.
+// This is the value originally passed to NewWebsocket.
+func (ws *Websocket) Raw() *websocket.Conn {
+	return ws.conn
+}
+
+// WithReadTimeout sets timeout duration for WebSocket connection reads.
+//
+// By default no timeout is used.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.WithReadTimeout(time.Second).Expect().Text().Equal("hello")
+func (ws *Websocket) WithReadTimeout(timeout time.Duration) *Websocket {
+	ws.readTimeout = timeout
+	return ws
+}
+
+// WithWriteTimeout sets timeout duration for WebSocket connection writes.
+//
+// By default no timeout is used.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.WithWriteTimeout(time.Second).WriteText("hello")
+func (ws *Websocket) WithWriteTimeout(timeout time.Duration) *Websocket {
+	ws.writeTimeout = timeout
+	return ws
+}
+
+// Subprotocol returns a new String object that may be used to inspect
+// negotiated protocol for the connection.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.Subprotocol().Equal("chat")
+func (ws *Websocket) Subprotocol() *String {
+	if ws.chain.failed() || ws.conn == nil {
+		return &String{ws.chain.enter(".Subprotocol"), ""}
+	}
+	return &String{ws.chain.enter(".Subprotocol"), ws.conn.Subprotocol()}
+}
+
+// Expect reads next message from WebSocket connection and returns a new
+// WebsocketMessage object that may be used to inspect it.
+//
+// If the connection was closed by peer, returned message is a close message
+// with close code and text sent by peer. If read fails for other reasons
+// (e.g. read timeout expires), failure is reported.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  msg := ws.Expect()
+//  msg.JSON().Object().ValueEqual("message", "hi")
+func (ws *Websocket) Expect() *WebsocketMessage {
+	if !ws.checkConn("read from") {
+		return makeWebsocketMessage(ws.chain.enter(".Expect()"))
+	}
+
+	if ws.readTimeout > 0 {
+		if err := ws.conn.SetReadDeadline(time.Now().Add(ws.readTimeout)); err != nil {
+			ws.chain.fail(err.Error())
+			return makeWebsocketMessage(ws.chain.enter(".Expect()"))
+		}
+	}
+
+	msg := makeWebsocketMessage(ws.chain.enter(".Expect()"))
+
+	var err error
+	msg.typ, msg.content, err = ws.conn.ReadMessage()
+
+	if err != nil {
+		cls, ok := err.(*websocket.CloseError)
+		if !ok {
+			ws.chain.fail("\nexpected read WebSocket connection, but got failure:\n  %s",
+				err.Error())
+			return makeWebsocketMessage(ws.chain.enter(".Expect()"))
+		}
+		msg.typ = websocket.CloseMessage
+		msg.closeCode = cls.Code
+		msg.content = []byte(cls.Text)
+	}
+
+	return msg
+}
+
+// WriteMessage writes message of given type with given content into
+// WebSocket connection.
+//
+// typ should be one of the message types defined in gorilla/websocket:
+// websocket.TextMessage, websocket.BinaryMessage, websocket.CloseMessage,
+// websocket.PingMessage or websocket.PongMessage.
+//
+// closeCode is used only for close messages. If omitted,
+// websocket.CloseNormalClosure is used.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.WriteMessage(websocket.TextMessage, []byte("hello"))
+//  ws.WriteMessage(websocket.CloseMessage, []byte("bye"), websocket.CloseGoingAway)
+func (ws *Websocket) WriteMessage(
+	typ int, content []byte, closeCode ...int) *Websocket {
+	if !ws.checkConn("write into") {
+		return ws
+	}
+
+	switch typ {
+	case websocket.TextMessage, websocket.BinaryMessage,
+		websocket.PingMessage, websocket.PongMessage:
+
+	case websocket.CloseMessage:
+		code := websocket.CloseNormalClosure
+		if len(closeCode) > 0 {
+			code = closeCode[0]
+		}
+		content = websocket.FormatCloseMessage(code, string(content))
+
+	default:
+		ws.chain.fail("\nunexpected WebSocket message type %s",
+			wsMessageTypeName(typ))
+		return ws
+	}
+
+	if ws.writeTimeout > 0 {
+		if err := ws.conn.SetWriteDeadline(time.Now().Add(ws.writeTimeout)); err != nil {
+			ws.chain.fail(err.Error())
+			return ws
+		}
+	}
+
+	if err := ws.conn.WriteMessage(typ, content); err != nil {
+		ws.chain.fail(
+			"\nexpected write into WebSocket connection, but got failure:\n  %s",
+			err.Error())
+	}
+
+	return ws
+}
+
+// WriteBytes writes binary message into WebSocket connection.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.WriteBytes([]byte{0x01, 0x02})
+func (ws *Websocket) WriteBytes(b []byte) *Websocket {
+	return ws.WriteMessage(websocket.BinaryMessage, b)
+}
+
+// WriteText writes text message into WebSocket connection.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.WriteText("hello")
+func (ws *Websocket) WriteText(s string) *Websocket {
+	return ws.WriteMessage(websocket.TextMessage, []byte(s))
+}
+
+// WriteJSON marshals given object into JSON and writes it as text message
+// into WebSocket connection.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.WriteJSON(map[string]string{"message": "hi"})
+func (ws *Websocket) WriteJSON(object interface{}) *Websocket {
+	if ws.chain.failed() {
+		return ws
+	}
+
+	b, err := json.Marshal(object)
+	if err != nil {
+		ws.chain.fail(err.Error())
+		return ws
+	}
+
+	return ws.WriteMessage(websocket.TextMessage, b)
+}
+
+// Close sends close message with websocket.CloseNormalClosure code and
+// closes underlying connection.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  defer ws.Close()
+func (ws *Websocket) Close() *Websocket {
+	return ws.CloseWithCode(websocket.CloseNormalClosure)
+}
+
+// CloseWithCode sends close message with given code and optional text and
+// closes underlying connection.
+//
+// Close message is not sent if the connection is failed, or if the close
+// message was already sent (e.g. automatically, in reply to close message
+// from peer). Underlying connection is closed anyway. Subsequent calls to
+// Close and CloseWithCode are ignored.
+//
+// Example:
+//  ws := NewWebsocket(t, conn)
+//  ws.CloseWithCode(websocket.CloseGoingAway, "bye")
+func (ws *Websocket) CloseWithCode(code int, text ...string) *Websocket {
+	if ws.conn == nil || ws.isClosed {
+		return ws
+	}
+
+	ws.isClosed = true
+
+	if !ws.chain.failed() {
+		var content string
+		if len(text) > 0 {
+			content = text[0]
+		}
+
+		err := ws.conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(code, content))
+
+		if err != nil && err != websocket.ErrCloseSent {
+			ws.chain.fail(
+				"\nexpected close WebSocket connection, but got failure:\n  %s",
+				err.Error())
+		}
+	}
+
+	ws.conn.Close()
+
+	return ws
+}
+
+func (ws *Websocket) checkConn(action string) bool {
+	if ws.chain.failed() {
+		return false
+	}
+	if ws.conn == nil {
+		ws.chain.fail("\nunexpected %s nil WebSocket connection", action)
+		return false
+	}
+	if ws.isClosed {
+		ws.chain.fail("\nunexpected %s closed WebSocket connection", action)
+		return false
+	}
+	return true
+}
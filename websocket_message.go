@@ -0,0 +1,182 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	"strconv"
+)
+
+// WebsocketMessage provides methods to inspect message read from WebSocket
+// connection.
+type WebsocketMessage struct {
+	chain     chain
+	typ       int
+	content   []byte
+	closeCode int
+}
+
+// NewWebsocketMessage returns a new WebsocketMessage object given a reporter
+// used to report failures and the message parameters to be inspected.
+//
+// reporter should not be nil. closeCode is used only for close messages.
+//
+// Example:
+//  m := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("content"), 0)
+//  m.Text().Equal("content")
+func NewWebsocketMessage(
+	reporter Reporter, typ int, content []byte, closeCode ...int) *WebsocketMessage {
+	m := makeWebsocketMessage(makeChain(reporter))
+	m.typ = typ
+	m.content = content
+	if len(closeCode) > 0 {
+		m.closeCode = closeCode[0]
+	}
+	return m
+}
+
+func makeWebsocketMessage(chain chain) *WebsocketMessage {
+	return &WebsocketMessage{
+		chain: chain,
+	}
+}
+
+// Raw returns underlying type, content and close code of WebSocket message.
+// These values are originally read from WebSocket connection.
+func (m *WebsocketMessage) Raw() (typ int, content []byte, closeCode int) {
+	return m.typ, m.content, m.closeCode
+}
+
+// CloseMessage succeedes if WebSocket message type is websocket.CloseMessage.
+//
+// If codes are given, CloseMessage also succeedes only if close code is
+// equal to one of them.
+//
+// Example:
+//  msg := ws.Expect()
+//  msg.CloseMessage(websocket.CloseNormalClosure, websocket.CloseGoingAway)
+func (m *WebsocketMessage) CloseMessage(codes ...int) *WebsocketMessage {
+	if !m.checkType(websocket.CloseMessage) {
+		return m
+	}
+
+	if len(codes) == 0 {
+		return m
+	}
+
+	for _, code := range codes {
+		if m.closeCode == code {
+			return m
+		}
+	}
+
+	m.chain.fail("\nexpected WebSocket close code one of:\n%s\n\nbut got:\n  %d",
+		dumpValue(codes), m.closeCode)
+
+	return m
+}
+
+// Body returns a new String object that may be used to inspect WebSocket
+// message content, regardless of message type.
+//
+// For close messages, content is the close text sent by peer.
+//
+// Example:
+//  msg := ws.Expect()
+//  msg.Body().NotEmpty()
+func (m *WebsocketMessage) Body() *String {
+	if m.chain.failed() {
+		return &String{m.chain.enter(".Body"), ""}
+	}
+	return &String{m.chain.enter(".Body"), string(m.content)}
+}
+
+// Text returns a new String object that may be used to inspect WebSocket
+// text message content.
+//
+// Text succeedes if message type is websocket.TextMessage.
+//
+// Example:
+//  msg := ws.Expect()
+//  msg.Text().Equal("hello")
+func (m *WebsocketMessage) Text() *String {
+	if !m.checkType(websocket.TextMessage) {
+		return &String{m.chain.enter(".Text"), ""}
+	}
+	return &String{m.chain.enter(".Text"), string(m.content)}
+}
+
+// Binary returns a new String object that may be used to inspect WebSocket
+// binary message content. The string contains raw message bytes.
+//
+// Binary succeedes if message type is websocket.BinaryMessage.
+//
+// Example:
+//  msg := ws.Expect()
+//  msg.Binary().Equal(string([]byte{0x01, 0x02}))
+func (m *WebsocketMessage) Binary() *String {
+	if !m.checkType(websocket.BinaryMessage) {
+		return &String{m.chain.enter(".Binary"), ""}
+	}
+	return &String{m.chain.enter(".Binary"), string(m.content)}
+}
+
+// JSON returns a new Value object that may be used to inspect JSON contents
+// of WebSocket message.
+//
+// JSON succeedes if message type is websocket.TextMessage or
+// websocket.BinaryMessage and if JSON may be decoded from message content.
+//
+// Example:
+//  msg := ws.Expect()
+//  msg.JSON().Array().Elements("foo", "bar")
+func (m *WebsocketMessage) JSON() *Value {
+	if !m.checkType(websocket.TextMessage, websocket.BinaryMessage) {
+		return &Value{m.chain.enter(".JSON"), nil}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(m.content, &value); err != nil {
+		m.chain.fail(err.Error())
+		return &Value{m.chain.enter(".JSON"), nil}
+	}
+
+	return &Value{m.chain.enter(".JSON"), value}
+}
+
+func (m *WebsocketMessage) checkType(types ...int) bool {
+	if m.chain.failed() {
+		return false
+	}
+
+	for _, typ := range types {
+		if m.typ == typ {
+			return true
+		}
+	}
+
+	names := []string{}
+	for _, typ := range types {
+		names = append(names, wsMessageTypeName(typ))
+	}
+
+	m.chain.fail("\nexpected WebSocket message type one of:\n%s\n\nbut got:\n  %s",
+		dumpValue(names), wsMessageTypeName(m.typ))
+
+	return false
+}
+
+func wsMessageTypeName(typ int) string {
+	switch typ {
+	case websocket.TextMessage:
+		return "text"
+	case websocket.BinaryMessage:
+		return "binary"
+	case websocket.CloseMessage:
+		return "close"
+	case websocket.PingMessage:
+		return "ping"
+	case websocket.PongMessage:
+		return "pong"
+	}
+	return "unknown(" + strconv.Itoa(typ) + ")"
+}
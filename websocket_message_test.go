@@ -0,0 +1,108 @@
+package httpexpect
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWebsocketMessageFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	msg := makeWebsocketMessage(chain)
+
+	msg.chain.assertFailed(t)
+
+	msg.CloseMessage().chain.assertFailed(t)
+	msg.Body().chain.assertFailed(t)
+	msg.Text().chain.assertFailed(t)
+	msg.Binary().chain.assertFailed(t)
+	msg.JSON().chain.assertFailed(t)
+}
+
+func TestWebsocketMessageText(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("hello"))
+
+	typ, content, code := msg.Raw()
+	assert.Equal(t, websocket.TextMessage, typ)
+	assert.Equal(t, []byte("hello"), content)
+	assert.Equal(t, 0, code)
+
+	msg.Text().Equal("hello")
+	msg.Body().Equal("hello")
+	msg.chain.assertOK(t)
+
+	msg.Binary()
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+
+	msg.CloseMessage()
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+
+	msg.JSON()
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+}
+
+func TestWebsocketMessageBinary(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	msg := NewWebsocketMessage(reporter, websocket.BinaryMessage, []byte{1, 2})
+
+	msg.Binary().Equal(string([]byte{1, 2}))
+	msg.chain.assertOK(t)
+
+	msg.Text()
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+}
+
+func TestWebsocketMessageJSON(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte(`[1, "a"]`))
+
+	msg.JSON().Array().Elements(1, "a")
+	msg.chain.assertOK(t)
+
+	msg = NewWebsocketMessage(reporter, websocket.BinaryMessage, []byte(`{"a": 1}`))
+
+	msg.JSON().Object().ValueEqual("a", 1)
+	msg.chain.assertOK(t)
+}
+
+func TestWebsocketMessageClose(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	msg := NewWebsocketMessage(reporter, websocket.CloseMessage, []byte("bye"),
+		websocket.CloseGoingAway)
+
+	msg.CloseMessage()
+	msg.chain.assertOK(t)
+
+	msg.CloseMessage(websocket.CloseGoingAway)
+	msg.chain.assertOK(t)
+
+	msg.CloseMessage(websocket.CloseNormalClosure, websocket.CloseGoingAway)
+	msg.chain.assertOK(t)
+
+	msg.Body().Equal("bye")
+	msg.chain.assertOK(t)
+
+	msg.CloseMessage(websocket.CloseNormalClosure)
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+
+	msg.Text()
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+
+	msg.JSON()
+	msg.chain.assertFailed(t)
+	msg.chain.reset()
+}
@@ -0,0 +1,175 @@
+package httpexpect
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createWebsocketHandler() http.Handler {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"echo"},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			typ, content, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if typ == websocket.TextMessage && string(content) == "close" {
+				conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "bye"))
+				continue
+			}
+			if err := conn.WriteMessage(typ, content); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func dialWebsocket(t *testing.T, server *httptest.Server) *websocket.Conn {
+	dialer := websocket.Dialer{
+		Subprotocols: []string{"echo"},
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestWebsocketFailed(t *testing.T) {
+	chain := makeChain(newMockReporter(t))
+
+	chain.fail("fail")
+
+	ws := makeWebsocket(chain, nil)
+
+	ws.chain.assertFailed(t)
+
+	ws.Subprotocol().chain.assertFailed(t)
+	ws.Expect().chain.assertFailed(t)
+	ws.WriteMessage(websocket.TextMessage, []byte("a")).chain.assertFailed(t)
+	ws.WriteBytes([]byte("a")).chain.assertFailed(t)
+	ws.WriteText("a").chain.assertFailed(t)
+	ws.WriteJSON(map[string]string{"a": "b"}).chain.assertFailed(t)
+	ws.Close().chain.assertFailed(t)
+	ws.CloseWithCode(websocket.CloseGoingAway).chain.assertFailed(t)
+}
+
+func TestWebsocketNil(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	ws := NewWebsocket(reporter, nil)
+
+	ws.chain.assertFailed(t)
+
+	assert.True(t, ws.Raw() == nil)
+}
+
+func TestWebsocketEcho(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler())
+	defer server.Close()
+
+	conn := dialWebsocket(t, server)
+
+	reporter := newMockReporter(t)
+
+	ws := NewWebsocket(reporter, conn).
+		WithReadTimeout(time.Second).
+		WithWriteTimeout(time.Second)
+
+	assert.Equal(t, conn, ws.Raw())
+
+	ws.Subprotocol().Equal("echo")
+
+	ws.WriteText("hello")
+	ws.Expect().Text().Equal("hello")
+
+	ws.WriteBytes([]byte{1, 2, 3})
+	ws.Expect().Binary().Equal(string([]byte{1, 2, 3}))
+
+	ws.WriteJSON(map[string]interface{}{"key": "value"})
+	ws.Expect().JSON().Object().ValueEqual("key", "value")
+
+	ws.WriteMessage(websocket.TextMessage, []byte("raw"))
+	ws.Expect().Body().Equal("raw")
+
+	ws.chain.assertOK(t)
+
+	ws.Close()
+	ws.chain.assertOK(t)
+
+	ws.Close()
+	ws.chain.assertOK(t)
+
+	ws.WriteText("hello")
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketPeerClose(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler())
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	ws := NewWebsocket(reporter, dialWebsocket(t, server)).
+		WithReadTimeout(time.Second)
+
+	ws.WriteText("close")
+
+	msg := ws.Expect()
+	msg.CloseMessage(websocket.CloseGoingAway)
+	msg.Body().Equal("bye")
+	msg.chain.assertOK(t)
+
+	ws.CloseWithCode(websocket.CloseNormalClosure, "bye")
+	ws.chain.assertOK(t)
+}
+
+func TestWebsocketReadTimeout(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler())
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	ws := NewWebsocket(reporter, dialWebsocket(t, server)).
+		WithReadTimeout(time.Millisecond * 10)
+
+	defer ws.Close()
+
+	ws.Expect()
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketBadMessage(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler())
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	ws := NewWebsocket(reporter, dialWebsocket(t, server))
+
+	defer ws.Close()
+
+	ws.WriteMessage(100, []byte("hello"))
+	ws.chain.assertFailed(t)
+	ws.chain.reset()
+
+	ws.WriteJSON(func() {})
+	ws.chain.assertFailed(t)
+	ws.chain.reset()
+}